@@ -7,48 +7,97 @@ import (
 	"os"
 	"time"
 
+	"github.com/jcornudella/hotbrew/internal/discover"
 	"github.com/jcornudella/hotbrew/internal/store"
 	hsync "github.com/jcornudella/hotbrew/internal/sync"
+	"github.com/jcornudella/hotbrew/pkg/profile"
 	"github.com/jcornudella/hotbrew/pkg/source"
-
-	// RSS source for feed auto-detection
-	"github.com/jcornudella/hotbrew/internal/sources/rss"
 )
 
 // Add handles `hotbrew add <url>`.
-// Auto-detects RSS feed, inserts source, and runs initial sync.
+// Finds the feed for the URL — the URL itself, a feed advertised by the
+// page, or one at a common path — inserts the source, and runs an initial sync.
 func Add(st *store.Store, args []string) {
 	if len(args) < 1 {
-		fmt.Println("Usage: hotbrew add <feed-url> [name]")
+		fmt.Println("Usage: hotbrew add <url> [name]")
 		fmt.Println("\nExamples:")
+		fmt.Println("  hotbrew add https://go.dev/blog")
 		fmt.Println("  hotbrew add https://blog.golang.org/feed.atom")
 		fmt.Println("  hotbrew add https://simonwillison.net/atom/everything/ \"Simon Willison\"")
 		os.Exit(1)
 	}
 
-	feedURL := args[0]
-	name := feedURL
+	pageURL := args[0]
+
+	fmt.Printf("  Looking for feeds at %s...\n", pageURL)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	feeds, err := discover.Find(ctx, pageURL)
+	cancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(feeds) == 0 {
+		fmt.Fprintf(os.Stderr, "No feed found at %s\n", pageURL)
+		os.Exit(1)
+	}
+
+	feed := feeds[0]
+	if len(feeds) > 1 {
+		fmt.Printf("  Found %d feeds:\n", len(feeds))
+		for i, f := range feeds {
+			fmt.Printf("    %d. %s (%s)\n", i+1, feedLabel(f), f.URL)
+		}
+		fmt.Println("  Using the first. To add another, run 'hotbrew add' with its URL.")
+	}
+
+	if existing, err := st.ListSources(); err == nil {
+		for _, s := range existing {
+			if s.URL == feed.URL {
+				fmt.Printf("Already subscribed: #%d %s (%s)\n", s.ID, s.Name, s.URL)
+				return
+			}
+		}
+	}
+
+	name := feed.Title
 	if len(args) > 1 {
 		name = args[1]
 	}
+	if name == "" {
+		name = feed.URL
+	}
 
 	// Create the source in the store.
-	sourceID, err := st.InsertSource(name, "rss", feedURL, "📰", nil)
+	sourceID, err := st.InsertSource(name, feed.Kind, feed.URL, "📰", nil)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error adding source: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Printf("✓ Added source #%d: %s\n", sourceID, name)
+	fmt.Printf("  Feed: %s (%s)\n", feed.URL, feed.Kind)
 	fmt.Println("  Fetching initial items...")
 
 	// Do an initial sync for this source.
-	src := rss.New(name, feedURL, "📰")
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	source.DefaultClient.SetValidatorStore(st)
+	source.DefaultClient.SetTokenStore(st)
+	src, err := source.FromSpec(profile.SourceSpec{
+		Driver:  feed.Kind,
+		Name:    name,
+		Icon:    "📰",
+		FeedURL: feed.URL,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	result := hsync.SyncSource(ctx, st, "rss", src, source.Config{
-		Enabled: true,
+	// The first sync gets its own deadline, however long discovery took.
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	result := hsync.SyncSource(ctx, st, hsync.StoreKey(feed.Kind, sourceID), src, source.Config{
+		Enabled:  true,
 		Settings: map[string]any{"max": 20},
 	})
 
@@ -57,5 +106,14 @@ func Add(st *store.Store, args []string) {
 		fmt.Println("  Source saved. It will be fetched on next sync.")
 	} else {
 		fmt.Printf("  ✓ Fetched %d items\n", result.ItemCount)
+		fmt.Println("  New items will be fetched on every sync.")
+	}
+}
+
+// feedLabel names a discovered feed for display.
+func feedLabel(f discover.Feed) string {
+	if f.Title == "" {
+		return "untitled " + f.Kind + " feed"
 	}
+	return f.Title
 }
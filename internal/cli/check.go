@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/config"
+	hsync "github.com/jcornudella/hotbrew/internal/sync"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// checkResult is the outcome of checking one profile entry.
+type checkResult struct {
+	spec     profile.SourceSpec
+	err      error  // the entry is invalid or its fetch failed
+	skipped  string // why the entry was not fetched
+	status   int    // HTTP status of the last response, 0 if none
+	items    int
+	newest   time.Time
+	warnings []string
+	duration time.Duration
+}
+
+// SourcesCheck handles `hotbrew sources check [key]` — validates the active
+// profile's entries and fetches each once without touching the store. It
+// returns an error when any entry fails, so it can gate CI.
+func SourcesCheck(cfg *config.Config, key string) error {
+	name := cfg.GetProfileName()
+	var specs []profile.SourceSpec
+	for _, spec := range profile.Load(name).Sources {
+		if key == "" || spec.Key == key {
+			specs = append(specs, spec)
+		}
+	}
+	if len(specs) == 0 {
+		if key != "" {
+			return fmt.Errorf("no entry with key %q in profile %q", key, name)
+		}
+		return fmt.Errorf("profile %q has no sources", name)
+	}
+
+	fmt.Printf("☕ Checking %d sources in profile %q...\n\n", len(specs), name)
+
+	keys := map[string]int{}
+	for _, spec := range specs {
+		keys[spec.Key]++
+	}
+
+	results := make([]checkResult, len(specs))
+	sem := make(chan struct{}, cfg.GetSyncWorkers())
+	var wg sync.WaitGroup
+	for i, spec := range specs {
+		if keys[spec.Key] > 1 {
+			results[i] = checkResult{spec: spec, err: fmt.Errorf("key %q is used by %d entries", spec.Key, keys[spec.Key])}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, spec profile.SourceSpec) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = checkSpec(cfg, spec)
+		}(i, spec)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		label := fmt.Sprintf("%s (%s)", r.spec.Key, r.spec.Driver)
+		switch {
+		case r.err != nil:
+			failed++
+			fmt.Printf("  ✗ %s: %v\n", label, r.err)
+		case r.skipped != "":
+			fmt.Printf("  · %s: skipped, %s\n", label, r.skipped)
+		default:
+			mark := "✓"
+			if len(r.warnings) > 0 {
+				mark = "⚠"
+			}
+			parts := []string{fmt.Sprintf("%d items", r.items)}
+			if r.status != 0 {
+				parts = append([]string{fmt.Sprintf("HTTP %d", r.status)}, parts...)
+			}
+			if !r.newest.IsZero() {
+				parts = append(parts, "newest "+formatAge(r.newest))
+			}
+			fmt.Printf("  %s %s: %s (%s)\n", mark, label, strings.Join(parts, ", "),
+				r.duration.Round(10*time.Millisecond))
+		}
+		for _, w := range r.warnings {
+			fmt.Printf("      warning: %s\n", w)
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sources failed", failed, len(results))
+	}
+	fmt.Println("All sources OK")
+	return nil
+}
+
+// checkSpec validates one entry and, if it is enabled, fetches it.
+func checkSpec(cfg *config.Config, spec profile.SourceSpec) checkResult {
+	res := checkResult{spec: spec}
+	switch {
+	case spec.Key == "":
+		res.err = errors.New("key is required")
+		return res
+	case spec.Driver == "":
+		res.err = errors.New("driver is required")
+		return res
+	}
+	if _, ok := source.LookupDriver(spec.Driver); !ok {
+		res.err = fmt.Errorf("unknown driver %q (see 'hotbrew drivers')", spec.Driver)
+		return res
+	}
+	srcCfg, ok := hsync.SpecConfig(cfg, spec)
+	if ok {
+		spec.Settings = srcCfg.Settings
+	}
+	src, err := source.FromSpec(spec)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	if !ok {
+		res.skipped = fmt.Sprintf("config entry %q is missing or disabled", spec.ConfigKey)
+		return res
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.GetSyncTimeout())
+	defer cancel()
+	ctx, trace := source.WithTrace(ctx)
+
+	start := time.Now()
+	section, err := src.Fetch(ctx, srcCfg)
+	res.duration = time.Since(start)
+	res.status = trace.Status()
+	if err != nil {
+		res.err = fmt.Errorf("fetch: %w", err)
+		return res
+	}
+	if section == nil {
+		res.warnings = append(res.warnings, "no section returned")
+		return res
+	}
+
+	res.items = len(section.Items)
+	res.warnings = itemWarnings(section.Items)
+	for _, item := range section.Items {
+		if item.Timestamp.After(res.newest) && !item.Timestamp.After(time.Now().Add(time.Hour)) {
+			res.newest = item.Timestamp
+		}
+	}
+	return res
+}
+
+// itemWarnings reports items that parsed but will display or dedupe badly.
+func itemWarnings(items []source.Item) []string {
+	if len(items) == 0 {
+		return []string{"no items"}
+	}
+	var noTitle, noURL, noTime, future, dupes int
+	seen := map[string]bool{}
+	for _, item := range items {
+		if strings.TrimSpace(item.Title) == "" {
+			noTitle++
+		}
+		if item.URL == "" {
+			noURL++
+		}
+		switch {
+		case item.Timestamp.IsZero():
+			noTime++
+		case item.Timestamp.After(time.Now().Add(time.Hour)):
+			future++
+		}
+		if item.ID != "" {
+			if seen[item.ID] {
+				dupes++
+			}
+			seen[item.ID] = true
+		}
+	}
+
+	var warnings []string
+	add := func(n int, what string) {
+		if n > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d of %d items %s", n, len(items), what))
+		}
+	}
+	add(noTitle, "have no title")
+	add(noURL, "have no URL")
+	add(noTime, "have no timestamp")
+	add(future, "are dated in the future")
+	add(dupes, "repeat an earlier item's ID")
+	return warnings
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/config"
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/internal/store"
+	hsync "github.com/jcornudella/hotbrew/internal/sync"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// Drivers handles `hotbrew drivers` — lists the source drivers a profile
+// entry can name, with the fields each accepts.
+func Drivers() {
+	fmt.Println("☕ Drivers:")
+	fmt.Println()
+	for _, d := range source.Drivers() {
+		fmt.Printf("  %s — %s\n", d.Name, d.Help)
+		printFields(d.Fields)
+		fmt.Println()
+	}
+	fmt.Println("  Every driver also accepts:")
+	printFields(hsync.CommonSettings)
+}
+
+func printFields(fields []source.Field) {
+	for _, f := range fields {
+		name := f.Name
+		if f.Setting {
+			name = "settings." + name
+		}
+		var notes []string
+		notes = append(notes, f.Type)
+		if f.Required {
+			notes = append(notes, "required")
+		}
+		fmt.Printf("      %-26s %-18s %s\n", name, strings.Join(notes, ", "), f.Help)
+	}
+}
+
+// DriverTest handles `hotbrew drivers test <key>` — fetches one source from
+// the active profile or the store and prints what it extracted, without
+// storing anything.
+func DriverTest(cfg *config.Config, st *store.Store, key string) error {
+	registry := hsync.BuildRegistry(cfg, st)
+	src, ok := registry.Get(key)
+	if !ok {
+		// Say why the entry was skipped, if it is in the profile at all.
+		for _, spec := range profile.Load(cfg.GetProfileName()).Sources {
+			if spec.Key != key {
+				continue
+			}
+			if _, err := source.FromSpec(spec); err != nil {
+				return fmt.Errorf("source %q: %w", key, err)
+			}
+			return fmt.Errorf("source %q is disabled in config", key)
+		}
+		return fmt.Errorf("no source with key %q (see 'hotbrew sources' or your profile)", key)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.GetSyncTimeout())
+	defer cancel()
+
+	start := time.Now()
+	section, err := src.Fetch(ctx, registry.Config(key))
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", key, err)
+	}
+
+	fmt.Printf("%s %s — %d items in %s\n\n", src.Icon(), src.Name(), len(section.Items),
+		time.Since(start).Round(time.Millisecond))
+	for i, item := range section.Items {
+		fmt.Printf("  %2d. %s\n", i+1, sanitize.Text(item.Title))
+		if item.URL != "" {
+			fmt.Printf("      %s\n", sanitize.Text(item.URL))
+		}
+		fmt.Printf("      %s · %s", item.Timestamp.Local().Format("Jan 2 2006, 3:04 PM"), item.Priority)
+		if item.Category != "" {
+			fmt.Printf(" · %s", item.Category)
+		}
+		fmt.Println()
+		if item.Subtitle != "" {
+			subtitle := sanitize.Text(item.Subtitle)
+			if r := []rune(subtitle); len(r) > 100 {
+				subtitle = string(r[:97]) + "..."
+			}
+			fmt.Printf("      %s\n", subtitle)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/store"
+)
+
+// historyWindow is how far back `hotbrew sync --history` looks when
+// judging source health.
+const historyWindow = 7 * 24 * time.Hour
+
+// sourceHealth summarizes one source's recent sync results.
+type sourceHealth struct {
+	key          string
+	failures     int       // consecutive failed syncs, most recent first
+	failingSince time.Time // start of the oldest failure in the streak
+	lastError    string
+	lastStatus   int
+	lastSuccess  *time.Time
+	lastNewItems *time.Time
+}
+
+// SyncHistory handles `hotbrew sync --history` — shows recent sync runs and
+// flags sources that keep failing or have stopped producing new items.
+func SyncHistory(st *store.Store, runs int) {
+	recent, err := st.ListSyncRuns(runs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading sync history: %v\n", err)
+		os.Exit(1)
+	}
+	if len(recent) == 0 {
+		fmt.Println("No syncs recorded yet. Run 'hotbrew sync' first.")
+		return
+	}
+
+	fmt.Println("☕ Recent syncs:")
+	fmt.Println()
+	for _, r := range recent {
+		duration := "running"
+		if r.FinishedAt != nil {
+			duration = r.FinishedAt.Sub(r.StartedAt).Round(time.Second).String()
+		}
+		fmt.Printf("  #%-4d %s  %d sources, %d new items", r.ID,
+			r.StartedAt.Local().Format("Jan 2 15:04"), r.Sources, r.Inserted)
+		if r.Errors > 0 {
+			fmt.Printf(", %d errors", r.Errors)
+		}
+		fmt.Printf(" (%s)\n", duration)
+	}
+
+	results, err := st.SyncResultsSince(time.Now().Add(-historyWindow))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading sync results: %v\n", err)
+		os.Exit(1)
+	}
+	health := summarizeHealth(results)
+
+	var failing, stale []sourceHealth
+	healthy := 0
+	for _, h := range health {
+		switch {
+		case h.failures > 0:
+			failing = append(failing, h)
+		case h.lastNewItems == nil && h.lastSuccess != nil:
+			stale = append(stale, h)
+		default:
+			healthy++
+		}
+	}
+
+	if len(failing) > 0 {
+		fmt.Println()
+		fmt.Println("Failing sources:")
+		fmt.Println()
+		sort.Slice(failing, func(i, j int) bool {
+			return failing[i].failingSince.Before(failing[j].failingSince)
+		})
+		for _, h := range failing {
+			fmt.Printf("  ✗ %s: failed %d times in a row, since %s\n",
+				h.key, h.failures, formatAge(h.failingSince))
+			if h.lastStatus > 0 {
+				fmt.Printf("      HTTP %d: %s\n", h.lastStatus, h.lastError)
+			} else {
+				fmt.Printf("      %s\n", h.lastError)
+			}
+			if h.lastSuccess != nil {
+				fmt.Printf("      Last success: %s\n", formatAge(*h.lastSuccess))
+			} else {
+				fmt.Println("      Last success: not in the past week")
+			}
+		}
+	}
+
+	if len(stale) > 0 {
+		fmt.Println()
+		fmt.Println("No new items in the past week:")
+		fmt.Println()
+		for _, h := range stale {
+			fmt.Printf("  ⚠ %s\n", h.key)
+		}
+	}
+
+	fmt.Printf("\n%d healthy, %d failing, %d stale\n", healthy, len(failing), len(stale))
+}
+
+// summarizeHealth groups results (newest first) by source key.
+func summarizeHealth(results []store.SyncResultRecord) []sourceHealth {
+	byKey := make(map[string]*sourceHealth)
+	var keys []string
+	streakDone := make(map[string]bool)
+
+	for _, r := range results {
+		h, ok := byKey[r.SourceKey]
+		if !ok {
+			h = &sourceHealth{key: r.SourceKey}
+			byKey[r.SourceKey] = h
+			keys = append(keys, r.SourceKey)
+		}
+
+		switch r.Status {
+		case "error":
+			if !streakDone[r.SourceKey] {
+				if h.failures == 0 {
+					h.lastError = r.Error
+					h.lastStatus = r.HTTPStatus
+				}
+				h.failures++
+				h.failingSince = r.StartedAt
+			}
+		case "ok", "not_modified":
+			streakDone[r.SourceKey] = true
+			if h.lastSuccess == nil {
+				t := r.StartedAt
+				h.lastSuccess = &t
+			}
+		}
+		if r.Inserted > 0 && h.lastNewItems == nil {
+			t := r.StartedAt
+			h.lastNewItems = &t
+		}
+	}
+
+	sort.Strings(keys)
+	health := make([]sourceHealth, 0, len(keys))
+	for _, k := range keys {
+		health = append(health, *byKey[k])
+	}
+	return health
+}
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jcornudella/hotbrew/internal/opml"
+	"github.com/jcornudella/hotbrew/internal/store"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+)
+
+// ImportOptions holds options for `hotbrew import opml`.
+type ImportOptions struct {
+	Path       string
+	Profile    string // write sources into this profile instead of the store
+	ByCategory bool   // write one profile per top-level OPML folder
+}
+
+// ImportOPML handles `hotbrew import opml <file>` — subscribes to every feed
+// in an OPML file. Folders and category attributes become source tags.
+func ImportOPML(st *store.Store, opts ImportOptions) {
+	if opts.Path == "" {
+		fmt.Println("Usage: hotbrew import opml <file> [--profile <name> | --by-category]")
+		fmt.Println("\nExamples:")
+		fmt.Println("  hotbrew import opml feeds.opml")
+		fmt.Println("  hotbrew import opml feeds.opml --profile reading")
+		fmt.Println("  hotbrew import opml feeds.opml --by-category")
+		os.Exit(1)
+	}
+
+	f, err := os.Open(opts.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	doc, err := opml.Parse(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	feeds := doc.Feeds()
+	if len(feeds) == 0 {
+		fmt.Println("No feeds found in", opts.Path)
+		return
+	}
+
+	switch {
+	case opts.ByCategory:
+		groups := make(map[string][]opml.Feed)
+		var order []string
+		for _, feed := range feeds {
+			name := "imported"
+			if len(feed.Categories) > 0 {
+				name = slug(feed.Categories[0])
+				feed.Categories = feed.Categories[1:]
+			}
+			if _, ok := groups[name]; !ok {
+				order = append(order, name)
+			}
+			groups[name] = append(groups[name], feed)
+		}
+		for _, name := range order {
+			importToProfile(name, groups[name])
+		}
+	case opts.Profile != "":
+		importToProfile(opts.Profile, feeds)
+	default:
+		importToStore(st, feeds)
+	}
+}
+
+// importToStore adds feeds as sources in the store, skipping URLs that are
+// already subscribed.
+func importToStore(st *store.Store, feeds []opml.Feed) {
+	existing := make(map[string]bool)
+	if sources, err := st.ListSources(); err == nil {
+		for _, s := range sources {
+			existing[s.URL] = true
+		}
+	}
+
+	added, skipped := 0, 0
+	for _, feed := range feeds {
+		if existing[feed.URL] {
+			skipped++
+			continue
+		}
+		var settings map[string]any
+		if len(feed.Categories) > 0 {
+			settings = map[string]any{"tags": feed.Categories}
+		}
+		name := feed.Title
+		if name == "" {
+			name = feed.URL
+		}
+		id, err := st.InsertSource(name, feedDriver(feed), feed.URL, "📰", settings)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  ✗ %s: %v\n", name, err)
+			continue
+		}
+		existing[feed.URL] = true
+		fmt.Printf("  ✓ #%d %s%s\n", id, name, formatTags(feed.Categories))
+		added++
+	}
+
+	fmt.Printf("\nImported %d feeds", added)
+	if skipped > 0 {
+		fmt.Printf(" (%d already subscribed)", skipped)
+	}
+	fmt.Println()
+	if added > 0 {
+		fmt.Println("Run 'hotbrew sync' to fetch them.")
+	}
+}
+
+// importToProfile appends feeds to a profile manifest.
+func importToProfile(name string, feeds []opml.Feed) {
+	prof, err := profile.Read(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading profile %s: %v\n", name, err)
+		os.Exit(1)
+	}
+
+	keys := make(map[string]bool)
+	urls := make(map[string]bool)
+	for _, spec := range prof.Sources {
+		keys[spec.Key] = true
+		urls[spec.FeedURL] = true
+	}
+
+	added := 0
+	for _, feed := range feeds {
+		if urls[feed.URL] {
+			continue
+		}
+		title := feed.Title
+		if title == "" {
+			title = feed.URL
+		}
+		driver := feedDriver(feed)
+		key := driver + "-" + slug(title)
+		for i := 2; keys[key]; i++ {
+			key = fmt.Sprintf("%s-%s-%d", driver, slug(title), i)
+		}
+		keys[key] = true
+		urls[feed.URL] = true
+
+		prof.Sources = append(prof.Sources, profile.SourceSpec{
+			Key:     key,
+			Driver:  driver,
+			Name:    title,
+			Icon:    "📰",
+			FeedURL: feed.URL,
+			Tags:    feed.Categories,
+		})
+		added++
+	}
+
+	if err := profile.Save(name, prof.Sources); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving profile %s: %v\n", name, err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Profile %s: added %d of %d feeds\n", name, added, len(feeds))
+}
+
+// ExportOPML handles `hotbrew export opml` — writes store subscriptions and
+// the profile's feed_url sources as OPML. Tags become folders and categories.
+func ExportOPML(st *store.Store, prof *profile.Profile, w io.Writer) {
+	var feeds []opml.Feed
+	seen := make(map[string]bool)
+	add := func(title, url, kind string, tags []string) {
+		if url == "" || seen[url] {
+			return
+		}
+		seen[url] = true
+		feed := opml.Feed{Title: title, URL: url, Categories: tags}
+		if kind == "jsonfeed" {
+			feed.Type = kind
+		}
+		feeds = append(feeds, feed)
+	}
+
+	for _, spec := range prof.Sources {
+		add(spec.Name, spec.FeedURL, spec.Driver, spec.Tags)
+	}
+	if st != nil {
+		sources, err := st.ListSources()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing sources: %v\n", err)
+			os.Exit(1)
+		}
+		for _, s := range sources {
+			if !s.Enabled {
+				continue
+			}
+			var tags []string
+			if raw, ok := s.Settings["tags"].([]any); ok {
+				for _, t := range raw {
+					if tag, ok := t.(string); ok {
+						tags = append(tags, tag)
+					}
+				}
+			}
+			add(s.Name, s.URL, s.Kind, tags)
+		}
+	}
+
+	if err := opml.New("hotbrew subscriptions", feeds).Write(w); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing OPML: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// feedDriver picks the driver for an imported feed from its outline type.
+func feedDriver(feed opml.Feed) string {
+	if feed.Type == "jsonfeed" {
+		return "jsonfeed"
+	}
+	return "rss"
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug turns a title into a lowercase, dash-separated identifier.
+func slug(s string) string {
+	s = strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if s == "" {
+		return "feed"
+	}
+	return s
+}
+
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(tags, ", ") + "]"
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/jcornudella/hotbrew/internal/store"
 )
@@ -32,6 +33,9 @@ func Sources(st *store.Store) {
 		if s.SyncErrors > 0 {
 			status = fmt.Sprintf("⚠ (%d errors)", s.SyncErrors)
 		}
+		if s.BreakerUntil != nil && time.Now().Before(*s.BreakerUntil) {
+			status = fmt.Sprintf("⛔ (circuit open after %d errors)", s.SyncErrors)
+		}
 
 		lastSync := "never"
 		if s.LastSync != nil {
@@ -40,6 +44,10 @@ func Sources(st *store.Store) {
 
 		fmt.Printf("  %s %s #%d %s (%s)\n", status, s.Icon, s.ID, s.Name, s.Kind)
 		fmt.Printf("      Last sync: %s\n", lastSync)
+		if s.BreakerUntil != nil && time.Now().Before(*s.BreakerUntil) {
+			fmt.Printf("      Paused until: %s (hotbrew sync --force to retry now)\n",
+				s.BreakerUntil.Local().Format("Jan 2, 3:04 PM"))
+		}
 		if s.URL != "" {
 			fmt.Printf("      URL: %s\n", s.URL)
 		}
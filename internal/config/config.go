@@ -21,9 +21,15 @@ type Config struct {
 	// TRSS settings
 	DBPath       string `yaml:"db_path,omitempty"`
 	SyncInterval string `yaml:"sync_interval,omitempty"` // e.g. "15m", "1h"
+	SyncWorkers  int    `yaml:"sync_workers,omitempty"`  // sources fetched in parallel
+	SyncTimeout  string `yaml:"sync_timeout,omitempty"`  // per-source fetch timeout, e.g. "30s"
 	DigestWindow string `yaml:"digest_window,omitempty"` // e.g. "24h", "12h"
 	DigestMax    int    `yaml:"digest_max,omitempty"`    // max items in digest
 	StreamLog    string `yaml:"stream_log,omitempty"`    // path to stream.log
+
+	// ExtractDenylist lists extra domains never fetched for full-text
+	// extraction, on top of the built-in video, social and code hosts.
+	ExtractDenylist []string `yaml:"extract_denylist,omitempty"`
 }
 
 // CustomThemeConfig holds custom theme colors
@@ -154,6 +160,24 @@ func (c *Config) GetSyncInterval() time.Duration {
 	return 15 * time.Minute
 }
 
+// GetSyncWorkers returns how many sources may be fetched in parallel.
+func (c *Config) GetSyncWorkers() int {
+	if c.SyncWorkers > 0 {
+		return c.SyncWorkers
+	}
+	return 4
+}
+
+// GetSyncTimeout returns the per-source fetch timeout.
+func (c *Config) GetSyncTimeout() time.Duration {
+	if c.SyncTimeout != "" {
+		if d, err := time.ParseDuration(c.SyncTimeout); err == nil {
+			return d
+		}
+	}
+	return 30 * time.Second
+}
+
 // GetDigestWindow returns the digest window as a duration.
 func (c *Config) GetDigestWindow() time.Duration {
 	if c.DigestWindow != "" {
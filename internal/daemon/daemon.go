@@ -25,8 +25,12 @@ func pidFile() string {
 	return filepath.Join(home, ".config", "hotbrew", "daemon.pid")
 }
 
+// RegistryBuilder builds the set of sources to sync. It is called on every
+// cycle so feeds added while the daemon runs are picked up.
+type RegistryBuilder func(cfg *config.Config, st *store.Store) *source.Registry
+
 // Start launches the daemon in the foreground (intended to be backgrounded by the caller).
-func Start(cfg *config.Config, registry *source.Registry) error {
+func Start(cfg *config.Config, build RegistryBuilder) error {
 	// Check if already running.
 	if pid := readPID(); pid > 0 {
 		if processExists(pid) {
@@ -45,6 +49,8 @@ func Start(cfg *config.Config, registry *source.Registry) error {
 		return fmt.Errorf("open store: %w", err)
 	}
 	defer st.Close()
+	source.DefaultClient.SetValidatorStore(st)
+	source.DefaultClient.SetTokenStore(st)
 
 	interval := cfg.GetSyncInterval()
 	fmt.Printf("☕ Daemon started (PID %d, interval %s)\n", os.Getpid(), interval)
@@ -60,12 +66,12 @@ func Start(cfg *config.Config, registry *source.Registry) error {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	runCycle(ctx, st, cfg, registry)
+	runCycle(ctx, st, cfg, build(cfg, st))
 
 	for {
 		select {
 		case <-ticker.C:
-			runCycle(ctx, st, cfg, registry)
+			runCycle(ctx, st, cfg, build(cfg, st))
 		case sig := <-sigCh:
 			fmt.Printf("\n☕ Daemon stopping (%v)\n", sig)
 			return nil
@@ -75,10 +81,11 @@ func Start(cfg *config.Config, registry *source.Registry) error {
 
 // runCycle performs one sync + digest cycle.
 func runCycle(ctx context.Context, st *store.Store, cfg *config.Config, registry *source.Registry) {
-	syncCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
-	defer cancel()
-
-	results := hsync.SyncAll(syncCtx, st, registry)
+	results := hsync.SyncAll(ctx, st, registry, hsync.Options{
+		Workers:         cfg.GetSyncWorkers(),
+		Timeout:         cfg.GetSyncTimeout(),
+		ExtractDenylist: cfg.ExtractDenylist,
+	})
 	hsync.PrintResults(results)
 
 	// Generate digest and write to stream log.
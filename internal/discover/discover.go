@@ -0,0 +1,168 @@
+// Package discover finds the feeds published by a web page.
+package discover
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+
+	"github.com/jcornudella/hotbrew/internal/sources/jsonfeed"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// maxBody caps how much of a page or feed is read while probing.
+const maxBody = 4 << 20
+
+// commonPaths are probed, relative to the site root, when a page does not
+// advertise its feeds.
+var commonPaths = []string{
+	"/feed", "/feed.xml", "/rss", "/rss.xml", "/atom.xml",
+	"/index.xml", "/feed.json", "/feed/atom",
+}
+
+// feedTypes are the <link type> values that identify a feed.
+var feedTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+	"application/json":      true,
+}
+
+// Feed is a feed found for a page.
+type Feed struct {
+	URL   string
+	Title string
+	Kind  string // driver that reads it: "rss" (RSS or Atom) or "jsonfeed"
+}
+
+// Find returns the feeds for rawURL, best candidate first. If rawURL is
+// itself a feed it is the only result; otherwise the page's
+// <link rel="alternate"> tags are followed, and failing that a few common
+// feed paths are probed. The paths are also probed when the page itself
+// can't be fetched, since sites often serve feeds from pages that are
+// blocked or broken; its error is returned only if probing finds nothing.
+// Every returned feed has been fetched and parsed.
+func Find(ctx context.Context, rawURL string) ([]Feed, error) {
+	base, err := url.Parse(rawURL)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q", rawURL)
+	}
+
+	var candidates []string
+	body, pageErr := fetch(ctx, rawURL)
+	if pageErr == nil {
+		if feed, ok := parseFeed(rawURL, body); ok {
+			return []Feed{feed}, nil
+		}
+		base, candidates = alternates(base, body)
+	} else if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	probing := len(candidates) == 0
+	if probing {
+		for _, p := range commonPaths {
+			candidates = append(candidates, base.ResolveReference(&url.URL{Path: p}).String())
+		}
+	}
+
+	var feeds []Feed
+	for _, c := range candidates {
+		body, err := fetch(ctx, c)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		if feed, ok := parseFeed(c, body); ok {
+			feeds = append(feeds, feed)
+			if probing {
+				// The common paths are usually aliases of one feed.
+				break
+			}
+		}
+	}
+	if len(feeds) == 0 && pageErr != nil {
+		return nil, pageErr
+	}
+	return feeds, nil
+}
+
+// alternates returns the feed URLs a page advertises with
+// <link rel="alternate"> tags, and the base they resolve against, which a
+// <base href> may change.
+func alternates(base *url.URL, page []byte) (*url.URL, []string) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		return base, nil
+	}
+	if href, ok := doc.Find("base[href]").Attr("href"); ok {
+		if u, err := base.Parse(href); err == nil {
+			base = u
+		}
+	}
+
+	var candidates []string
+	seen := make(map[string]bool)
+	doc.Find("link[rel~=alternate][href]").Each(func(_ int, s *goquery.Selection) {
+		typ := strings.ToLower(strings.TrimSpace(s.AttrOr("type", "")))
+		if i := strings.IndexByte(typ, ';'); i >= 0 {
+			typ = strings.TrimSpace(typ[:i])
+		}
+		if !feedTypes[typ] {
+			return
+		}
+		u, err := base.Parse(strings.TrimSpace(s.AttrOr("href", "")))
+		if err != nil || seen[u.String()] {
+			return
+		}
+		seen[u.String()] = true
+		candidates = append(candidates, u.String())
+	})
+	return base, candidates
+}
+
+// fetch downloads url. It uses Client.Do rather than Client.Get so probing
+// does not record cache validators, which would turn the first real sync
+// into a 304.
+func fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, text/html;q=0.9, */*;q=0.8")
+	resp, err := source.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxBody))
+}
+
+// parseFeed reports whether body is a feed hotbrew can read.
+func parseFeed(url string, body []byte) (Feed, bool) {
+	if jsonfeed.Sniff(body) {
+		var f jsonfeed.Feed
+		if json.Unmarshal(body, &f) != nil {
+			return Feed{}, false
+		}
+		return Feed{URL: url, Title: strings.TrimSpace(f.Title), Kind: "jsonfeed"}, true
+	}
+	f, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	if err != nil || f.FeedType == "json" {
+		return Feed{}, false
+	}
+	return Feed{URL: url, Title: strings.TrimSpace(f.Title), Kind: "rss"}, true
+}
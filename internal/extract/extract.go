@@ -0,0 +1,212 @@
+// Package extract pulls the readable main text out of article pages.
+package extract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// maxBody caps how much of a page is downloaded.
+const maxBody = 4 << 20
+
+// WordsPerMinute is the reading speed used for reading-time estimates.
+const WordsPerMinute = 230
+
+// ErrNotArticle is returned for pages with no recognizable main content,
+// and for responses that aren't HTML.
+var ErrNotArticle = errors.New("no article content")
+
+// DefaultDenylist holds domains whose pages are not articles: video and
+// social sites, code hosts and discussion pages.
+var DefaultDenylist = []string{
+	"youtube.com", "youtu.be", "vimeo.com",
+	"twitter.com", "x.com", "bsky.app", "mastodon.social",
+	"github.com", "gitlab.com",
+	"news.ycombinator.com", "lobste.rs", "reddit.com",
+	"stackoverflow.com", "stackexchange.com",
+}
+
+var (
+	// unlikely and likely match class and id attributes of boilerplate
+	// and content containers respectively.
+	unlikely = regexp.MustCompile(`(?i)comment|sidebar|footer|masthead|menu|nav|share|social|related|promo|advert|sponsor|cookie|newsletter|subscribe|popup|breadcrumb|byline|author-bio`)
+	likely   = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story|text`)
+
+	// binaryExt marks URLs that point at documents rather than pages.
+	binaryExt = regexp.MustCompile(`(?i)\.(pdf|zip|gz|mp3|mp4|m4a|png|jpe?g|gif|webp|svg)$`)
+)
+
+// Article is the main content of a page.
+type Article struct {
+	Text  string // paragraphs separated by blank lines
+	Words int
+}
+
+// ReadingMinutes estimates how long the article takes to read, rounded up.
+func (a Article) ReadingMinutes() int {
+	if a.Words == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(a.Words) / WordsPerMinute))
+}
+
+// Denied reports whether rawURL should not be fetched for extraction: it
+// is not http(s), points at a binary document, or its host is on denylist
+// or a subdomain of an entry.
+func Denied(rawURL string, denylist []string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return true
+	}
+	if binaryExt.MatchString(u.Path) {
+		return true
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	for _, d := range denylist {
+		d = strings.TrimPrefix(strings.ToLower(d), "www.")
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch downloads pageURL and extracts its article. It uses Client.Do
+// rather than Client.Get so article pages don't fill the validator table.
+func Fetch(ctx context.Context, pageURL string) (Article, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return Article{}, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9")
+	resp, err := source.DefaultClient.Do(req)
+	if err != nil {
+		return Article{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Article{}, fmt.Errorf("%s: status %d", pageURL, resp.StatusCode)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "" && !strings.Contains(mediaType, "html") {
+		return Article{}, ErrNotArticle
+	}
+	return Parse(io.LimitReader(resp.Body, maxBody))
+}
+
+// Parse extracts the article from an HTML document. It scores the
+// containers of each paragraph, readability-style, by how much prose they
+// hold, then keeps the text blocks of the best one.
+func Parse(r io.Reader) (Article, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return Article{}, err
+	}
+
+	doc.Find("script, style, noscript, template, iframe, svg, canvas, form, button, nav, header, footer, aside").Remove()
+	doc.Find("[class], [id]").Each(func(_ int, s *goquery.Selection) {
+		if s.Is("html, body, article, main") {
+			return
+		}
+		attrs := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if unlikely.MatchString(attrs) && !likely.MatchString(attrs) {
+			s.Remove()
+		}
+	})
+
+	scores := map[*html.Node]float64{}
+	doc.Find("p, pre, td, blockquote").Each(func(_ int, p *goquery.Selection) {
+		text := strings.TrimSpace(p.Text())
+		if len(text) < 25 {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+		parent := p.Parent()
+		if parent.Length() == 0 {
+			return
+		}
+		scores[parent.Get(0)] += score
+		if grand := parent.Parent(); grand.Length() > 0 {
+			scores[grand.Get(0)] += score / 2
+		}
+	})
+
+	var best *goquery.Selection
+	bestScore := 0.0
+	for node, score := range scores {
+		s := goquery.NewDocumentFromNode(node).Selection
+		attrs := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if likely.MatchString(attrs) {
+			score += 25
+		}
+		score *= 1 - linkDensity(s)
+		if score > bestScore {
+			best, bestScore = s, score
+		}
+	}
+	if best == nil {
+		if best = doc.Find("article").First(); best.Length() == 0 {
+			return Article{}, ErrNotArticle
+		}
+	}
+
+	var blocks []string
+	collect(best, &blocks)
+	text := strings.Join(blocks, "\n\n")
+	words := len(strings.Fields(text))
+	if words == 0 {
+		return Article{}, ErrNotArticle
+	}
+	return Article{Text: text, Words: words}, nil
+}
+
+// collect appends the text of the block elements under s, in document
+// order. Nested blocks are taken whole with their outermost block.
+func collect(s *goquery.Selection, blocks *[]string) {
+	s.Children().Each(func(_ int, child *goquery.Selection) {
+		if !child.Is("p, pre, blockquote, li, h1, h2, h3, h4, h5, h6, dd, figcaption") {
+			collect(child, blocks)
+			return
+		}
+		var text string
+		if child.Is("pre") {
+			text = strings.TrimRight(child.Text(), "\n ")
+		} else {
+			text = strings.Join(strings.Fields(child.Text()), " ")
+		}
+		text = sanitize.Text(text)
+		if text == "" {
+			return
+		}
+		if child.Is("li") {
+			text = "• " + text
+		}
+		*blocks = append(*blocks, text)
+	})
+}
+
+// linkDensity is the share of s's text that sits inside links.
+func linkDensity(s *goquery.Selection) float64 {
+	total := len(strings.TrimSpace(s.Text()))
+	if total == 0 {
+		return 1
+	}
+	linked := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linked += len(strings.TrimSpace(a.Text()))
+	})
+	return float64(linked) / float64(total)
+}
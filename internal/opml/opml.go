@@ -0,0 +1,180 @@
+// Package opml reads and writes OPML 2.0 subscription lists.
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Document is an OPML file.
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+// Head holds document metadata.
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+// Body holds the top-level outlines.
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is a feed subscription or a folder of outlines.
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Category string    `xml:"category,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// Feed is a subscription flattened out of the outline tree.
+type Feed struct {
+	Title      string
+	URL        string
+	HTMLURL    string
+	Type       string   // outline type: "rss" (the default) or "jsonfeed"
+	Categories []string // enclosing folders, then the category attribute
+}
+
+// Parse reads an OPML document.
+func Parse(r io.Reader) (*Document, error) {
+	var doc Document
+	dec := xml.NewDecoder(r)
+	// Exported files in the wild are frequently declared as something
+	// other than UTF-8; the feeds we care about are ASCII URLs.
+	dec.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse opml: %w", err)
+	}
+	return &doc, nil
+}
+
+// Feeds returns every outline with an xmlUrl, depth first.
+func (d *Document) Feeds() []Feed {
+	var feeds []Feed
+	var walk func(outlines []Outline, folders []string)
+	walk = func(outlines []Outline, folders []string) {
+		for _, o := range outlines {
+			if o.XMLURL == "" {
+				name := strings.TrimSpace(firstNonEmpty(o.Text, o.Title))
+				next := folders
+				if name != "" {
+					next = append(append([]string(nil), folders...), name)
+				}
+				walk(o.Outlines, next)
+				continue
+			}
+			feeds = append(feeds, Feed{
+				Title:      strings.TrimSpace(firstNonEmpty(o.Title, o.Text)),
+				URL:        strings.TrimSpace(o.XMLURL),
+				HTMLURL:    o.HTMLURL,
+				Type:       strings.ToLower(strings.TrimSpace(o.Type)),
+				Categories: dedupe(append(append([]string(nil), folders...), splitCategory(o.Category)...)),
+			})
+			walk(o.Outlines, folders)
+		}
+	}
+	walk(d.Body.Outlines, nil)
+	return feeds
+}
+
+// New builds a document from feeds, grouping them into folders by their
+// first category. All categories are also kept in the category attribute.
+func New(title string, feeds []Feed) *Document {
+	doc := &Document{
+		Version: "2.0",
+		Head: Head{
+			Title:       title,
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	folders := make(map[string]int) // folder name -> index in Body.Outlines
+	for _, f := range feeds {
+		o := Outline{
+			Text:    f.Title,
+			Title:   f.Title,
+			Type:    firstNonEmpty(f.Type, "rss"),
+			XMLURL:  f.URL,
+			HTMLURL: f.HTMLURL,
+		}
+		if len(f.Categories) == 0 {
+			doc.Body.Outlines = append(doc.Body.Outlines, o)
+			continue
+		}
+		o.Category = "/" + strings.Join(f.Categories, ",/")
+
+		folder := f.Categories[0]
+		i, ok := folders[folder]
+		if !ok {
+			i = len(doc.Body.Outlines)
+			folders[folder] = i
+			doc.Body.Outlines = append(doc.Body.Outlines, Outline{Text: folder, Title: folder})
+		}
+		doc.Body.Outlines[i].Outlines = append(doc.Body.Outlines[i].Outlines, o)
+	}
+	return doc
+}
+
+// Write encodes the document with an XML header.
+func (d *Document) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// splitCategory parses a category attribute: comma-separated, slash-delimited
+// paths such as "/Tech/Go,/News". Each path segment becomes a category.
+func splitCategory(value string) []string {
+	var out []string
+	for _, path := range strings.Split(value, ",") {
+		for _, part := range strings.Split(path, "/") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := values[:0]
+	for _, v := range values {
+		key := strings.ToLower(v)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -1,5 +1,11 @@
 package sanitize
 
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
 // Text strips control characters (except newline and tab) to avoid ANSI/OSC injection.
 func Text(s string) string {
 	if s == "" {
@@ -18,3 +24,40 @@ func Text(s string) string {
 	}
 	return string(out)
 }
+
+var (
+	paraTags   = regexp.MustCompile(`(?i)</?(p|blockquote|pre|h[1-6])\b[^>]*>`)
+	breakTags  = regexp.MustCompile(`(?i)<br\s*/?>|</?(div|li)\b[^>]*>`)
+	anyTag     = regexp.MustCompile(`<[^>]*>`)
+	extraLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// StripHTML converts an HTML fragment, such as a comment body, to plain
+// text: block tags become line breaks, other tags are dropped and entities
+// are decoded. The result is passed through Text.
+func StripHTML(s string) string {
+	if s == "" {
+		return s
+	}
+	s = paraTags.ReplaceAllString(s, "\n\n")
+	s = breakTags.ReplaceAllString(s, "\n")
+	s = anyTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	s = extraLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return Text(strings.TrimSpace(s))
+}
+
+// FirstLine returns the first line of text, trimmed and cut to at most n
+// runes, for use as a title.
+func FirstLine(text string, n int) string {
+	line, _, _ := strings.Cut(text, "\n")
+	r := []rune(strings.TrimSpace(line))
+	if len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return string(r)
+}
@@ -3,6 +3,7 @@ package sinks
 import (
 	"fmt"
 
+	"github.com/jcornudella/hotbrew/pkg/source"
 	"github.com/jcornudella/hotbrew/pkg/trss"
 )
 
@@ -23,7 +24,14 @@ func (s *Stdout) Deliver(d *trss.Digest) error {
 			score = "⭐"
 		}
 
-		fmt.Printf("  %s %2d. %s\n", score, i+1, item.Title)
+		title := item.Title
+		if secs, ok := item.Meta["duration"].(float64); ok && secs > 0 {
+			title += " [" + source.FormatDuration(int(secs)) + "]"
+		} else if secs, ok := item.Meta["duration"].(int); ok && secs > 0 {
+			title += " [" + source.FormatDuration(secs) + "]"
+		}
+
+		fmt.Printf("  %s %2d. %s\n", score, i+1, title)
 		if item.Summary != "" {
 			summary := item.Summary
 			if len(summary) > 120 {
@@ -0,0 +1,23 @@
+// Package all registers every built-in source driver. Import it for its
+// side effects wherever sources are built from profile entries.
+package all
+
+import (
+	_ "github.com/jcornudella/hotbrew/internal/sources/arxiv"
+	_ "github.com/jcornudella/hotbrew/internal/sources/bluesky"
+	_ "github.com/jcornudella/hotbrew/internal/sources/execsource"
+	_ "github.com/jcornudella/hotbrew/internal/sources/github"
+	_ "github.com/jcornudella/hotbrew/internal/sources/hackernews"
+	_ "github.com/jcornudella/hotbrew/internal/sources/hnsearch"
+	_ "github.com/jcornudella/hotbrew/internal/sources/inbox"
+	_ "github.com/jcornudella/hotbrew/internal/sources/jsonfeed"
+	_ "github.com/jcornudella/hotbrew/internal/sources/lobsters"
+	_ "github.com/jcornudella/hotbrew/internal/sources/mastodon"
+	_ "github.com/jcornudella/hotbrew/internal/sources/podcast"
+	_ "github.com/jcornudella/hotbrew/internal/sources/reddit"
+	_ "github.com/jcornudella/hotbrew/internal/sources/rss"
+	_ "github.com/jcornudella/hotbrew/internal/sources/scrape"
+	_ "github.com/jcornudella/hotbrew/internal/sources/stackexchange"
+	_ "github.com/jcornudella/hotbrew/internal/sources/tldr"
+	_ "github.com/jcornudella/hotbrew/internal/sources/youtube"
+)
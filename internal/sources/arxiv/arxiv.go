@@ -6,20 +6,24 @@ package arxiv
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
-	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/jcornudella/hotbrew/pkg/profile"
 	"github.com/jcornudella/hotbrew/pkg/source"
+	"github.com/jcornudella/hotbrew/pkg/trss"
 )
 
 // Default categories matching llm-research-digest.
 var DefaultCategories = []string{"cs.CL", "cs.AI", "cs.LG", "cs.MA"}
 
 // Relevance keywords — expanded from llm-research-digest with more
-// practical LLM-building terms.
+// practical LLM-building terms. Used when a profile entry has no
+// relevance patterns of its own.
 var relevancePatterns = []string{
 	// Core LLM terms
 	`\bllm\b`, `\blarge language model`, `\bfoundation model`,
@@ -76,11 +80,39 @@ var relevancePatterns = []string{
 	`\btokeniz`, `\btoken\b`,
 }
 
-var relevanceRegex *regexp.Regexp
+// pattern is a compiled relevance pattern and the score each match adds.
+type pattern struct {
+	re     *regexp.Regexp
+	weight float64
+}
+
+// defaultRelevance is relevancePatterns with a weight of 1 each.
+var defaultRelevance []pattern
 
 func init() {
-	combined := strings.Join(relevancePatterns, "|")
-	relevanceRegex = regexp.MustCompile("(?i)" + combined)
+	for _, p := range relevancePatterns {
+		defaultRelevance = append(defaultRelevance, pattern{re: regexp.MustCompile("(?i)" + p), weight: 1})
+	}
+}
+
+// compileRelevance compiles a profile's relevance map of regular
+// expression to weight. Patterns match case-insensitively.
+func compileRelevance(weights map[string]float64) ([]pattern, error) {
+	exprs := make([]string, 0, len(weights))
+	for expr := range weights {
+		exprs = append(exprs, expr)
+	}
+	sort.Strings(exprs)
+
+	patterns := make([]pattern, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return nil, fmt.Errorf("relevance pattern %q: %w", expr, err)
+		}
+		patterns = append(patterns, pattern{re: re, weight: weights[expr]})
+	}
+	return patterns, nil
 }
 
 // arXiv Atom feed types.
@@ -114,22 +146,65 @@ type atomCat struct {
 	Term string `xml:"term,attr"`
 }
 
-// Source fetches papers from arXiv.
+// Source fetches papers from arXiv and ranks them by how well their title
+// and abstract match its relevance patterns. Papers by watched authors are
+// always included, whatever their category or score.
+//
+// Settings:
+//
+//	max          number of papers besides watched authors' (default 5)
+//	min_score    drop papers scoring below this (default 0)
+//	author_days  how far back to look for watched authors' papers (default 30)
 type Source struct {
 	name       string
 	icon       string
 	categories []string
+	relevance  []pattern
+	authors    []string
 }
 
-// New creates an arXiv source with specified categories.
-func New(name string, categories []string, icon string) *Source {
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "arxiv",
+		Help: "recent arXiv papers ranked by keyword relevance",
+		Fields: []source.Field{
+			{Name: "categories", Type: "strings", Help: "arXiv categories (default cs.CL, cs.AI, cs.LG, cs.MA)"},
+			{Name: "relevance", Type: "map", Help: "regular expression to weight (default: built-in LLM keywords)"},
+			{Name: "authors", Type: "strings", Help: "researchers whose papers always surface"},
+			source.MaxField(5),
+			{Name: "min_score", Type: "float", Setting: true, Help: "drop papers scoring below this (default 0)"},
+			{Name: "author_days", Type: "int", Setting: true, Help: "how far back to look for watched authors (default 30)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			return New(spec.Name, spec.Categories, spec.Relevance, spec.Authors, spec.Icon)
+		},
+	})
+}
+
+// New creates an arXiv source for categories. relevance maps regular
+// expressions to the score each match adds; empty means the built-in LLM
+// keywords, each worth 1. authors are full names to watch.
+func New(name string, categories []string, relevance map[string]float64, authors []string, icon string) (*Source, error) {
 	if icon == "" {
 		icon = "📄"
 	}
 	if len(categories) == 0 {
 		categories = DefaultCategories
 	}
-	return &Source{name: name, icon: icon, categories: categories}
+	patterns := defaultRelevance
+	if len(relevance) > 0 {
+		var err error
+		if patterns, err = compileRelevance(relevance); err != nil {
+			return nil, err
+		}
+	}
+	return &Source{
+		name:       name,
+		icon:       icon,
+		categories: categories,
+		relevance:  patterns,
+		authors:    authors,
+	}, nil
 }
 
 func (s *Source) Name() string        { return s.name }
@@ -137,10 +212,9 @@ func (s *Source) Icon() string        { return s.icon }
 func (s *Source) TTL() time.Duration  { return 1 * time.Hour }
 
 func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
-	maxItems := 5
-	if max, ok := cfg.Settings["max"].(int); ok {
-		maxItems = max
-	}
+	maxItems := cfg.Int("max", 5)
+	minScore := cfg.Float("min_score", 0)
+	authorSince := time.Now().AddDate(0, 0, -cfg.Int("author_days", 30))
 
 	// Build query: cat:cs.CL+OR+cat:cs.AI+OR+...
 	// Note: arXiv API uses + for spaces and +OR+ for boolean OR.
@@ -149,7 +223,6 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 	for i, cat := range s.categories {
 		catClauses[i] = "cat:" + cat
 	}
-	query := strings.Join(catClauses, "+OR+")
 
 	// Fetch more than needed so keyword filter has enough to work with.
 	fetchCount := maxItems * 10
@@ -157,73 +230,112 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 		fetchCount = 50
 	}
 
-	apiURL := fmt.Sprintf(
-		"https://export.arxiv.org/api/query?search_query=%s&sortBy=submittedDate&sortOrder=descending&max_results=%d",
-		query, fetchCount,
-	)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return nil, err
+	// A 304 means no new entries; it only stands for the whole fetch when
+	// every query returned one.
+	var errs []error
+	queries, read, notModified := 1, 0, 0
+	tally := func(err error) {
+		switch {
+		case errors.Is(err, source.ErrNotModified):
+			notModified++
+		case err != nil:
+			errs = append(errs, err)
+		default:
+			read++
+		}
 	}
-	req.Header.Set("User-Agent", "hotbrew/1.0 (terminal-rss)")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
+	entries, err := s.query(ctx, strings.Join(catClauses, "+OR+"), fetchCount)
+	tally(err)
+	if len(s.authors) > 0 {
+		queries++
+		auClauses := make([]string, len(s.authors))
+		for i, author := range s.authors {
+			auClauses[i] = "au:%22" + strings.Join(strings.Fields(author), "+") + "%22"
+		}
+		// arXiv asks API clients to wait three seconds between calls.
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(3 * time.Second):
+		}
+		watched, err := s.query(ctx, strings.Join(auClauses, "+OR+"), 50)
+		if err != nil && !errors.Is(err, source.ErrNotModified) {
+			err = fmt.Errorf("authors: %w", err)
+		}
+		tally(err)
+		entries = append(watched, entries...)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("arxiv: status %d", resp.StatusCode)
+	// Only fail the sync when neither query could be read.
+	if len(errs) > 0 && read == 0 {
+		return nil, errors.Join(errs...)
 	}
-
-	var feed atomFeed
-	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
-		return nil, fmt.Errorf("arxiv: parse: %w", err)
+	if notModified == queries {
+		return nil, source.ErrNotModified
 	}
 
-	// Score by keyword relevance and take top N.
+	// Score by keyword relevance; watched authors' papers skip the cut.
 	type scored struct {
-		entry atomEntry
-		score int
+		entry   atomEntry
+		id      string
+		score   float64
+		watched []string
 	}
 	var candidates []scored
+	seen := map[string]bool{}
 
-	for _, entry := range feed.Entries {
+	for _, entry := range entries {
+		id, ok := trss.ArxivID(entry.ID)
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		c := scored{entry: entry, id: id, watched: s.watchedAuthors(entry)}
+		if len(c.watched) > 0 && publishedAt(entry).Before(authorSince) {
+			c.watched = nil
+		}
 		text := entry.Title + " " + entry.Summary
-		matches := relevanceRegex.FindAllStringIndex(text, -1)
-		candidates = append(candidates, scored{entry: entry, score: len(matches)})
+		for _, p := range s.relevance {
+			c.score += p.weight * float64(len(p.re.FindAllStringIndex(text, -1)))
+		}
+		if len(c.watched) == 0 && c.score < minScore {
+			continue
+		}
+		candidates = append(candidates, c)
 	}
 
-	// Sort by score descending (keyword matches first, then recency).
-	for i := 0; i < len(candidates); i++ {
-		for j := i + 1; j < len(candidates); j++ {
-			if candidates[j].score > candidates[i].score {
-				candidates[i], candidates[j] = candidates[j], candidates[i]
-			}
+	// Watched authors first, then by score; ties keep arXiv's newest-first order.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		wi, wj := len(candidates[i].watched) > 0, len(candidates[j].watched) > 0
+		if wi != wj {
+			return wi
 		}
-	}
+		return candidates[i].score > candidates[j].score
+	})
 
-	// Take top maxItems — even if some have 0 keyword matches,
-	// they're still from the right categories and recently published.
+	// Take every watched paper plus the top maxItems others — even if some
+	// have no keyword matches, they're still from the right categories and
+	// recently published.
 	var items []source.Item
+	others := 0
 	for _, c := range candidates {
-		if len(items) >= maxItems {
-			break
+		if len(c.watched) == 0 {
+			if others >= maxItems {
+				break
+			}
+			others++
 		}
 
 		entry := c.entry
-		published, _ := time.Parse(time.RFC3339, entry.Published)
-		if published.IsZero() {
-			published, _ = time.Parse(time.RFC3339, entry.Updated)
-		}
+		published := publishedAt(entry)
 
-		// Get the abstract link (HTML version).
-		paperURL := entry.ID
+		// Link the versionless abstract page, so every version and the
+		// PDF dedupe to one item.
+		paperURL := "https://arxiv.org/abs/" + c.id
+		pdfURL := "https://arxiv.org/pdf/" + c.id
 		for _, link := range entry.Links {
-			if link.Rel == "alternate" {
-				paperURL = link.Href
+			if link.Type == "application/pdf" {
+				pdfURL = link.Href
 				break
 			}
 		}
@@ -247,7 +359,7 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 		}
 
 		priority := source.Medium
-		if c.score >= 8 {
+		if len(c.watched) > 0 || c.score >= 8 {
 			priority = source.Urgent
 		} else if c.score >= 4 {
 			priority = source.High
@@ -260,9 +372,20 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 			abstract = abstract[:247] + "..."
 		}
 
+		metadata := map[string]any{
+			"authors":         authors,
+			"tags":            tags,
+			"relevance_score": c.score,
+			"arxiv_id":        c.id,
+			"pdf_url":         pdfURL,
+		}
+		if len(c.watched) > 0 {
+			metadata["watched_authors"] = c.watched
+		}
+
 		items = append(items, source.Item{
-			ID:        entry.ID,
-			Title:     strings.TrimSpace(entry.Title),
+			ID:        "arxiv-" + c.id,
+			Title:     strings.Join(strings.Fields(entry.Title), " "),
 			Subtitle:  abstract,
 			Body:      fmt.Sprintf("Authors: %s\n\n%s", authorStr, entry.Summary),
 			URL:       paperURL,
@@ -272,12 +395,9 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 			Icon:      s.icon,
 			Actions: []source.Action{
 				{Key: "o", Label: "open", Command: paperURL},
+				{Key: "p", Label: "pdf", Command: pdfURL},
 			},
-			Metadata: map[string]any{
-				"authors":         authors,
-				"tags":            tags,
-				"relevance_score": c.score,
-			},
+			Metadata: metadata,
 		})
 	}
 
@@ -288,3 +408,66 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 		Items:    items,
 	}, nil
 }
+
+// query runs an arXiv API search, newest submissions first.
+func (s *Source) query(ctx context.Context, query string, count int) ([]atomEntry, error) {
+	apiURL := fmt.Sprintf(
+		"https://export.arxiv.org/api/query?search_query=%s&sortBy=submittedDate&sortOrder=descending&max_results=%d",
+		query, count,
+	)
+
+	resp, err := source.DefaultClient.Get(ctx, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("arxiv: status %d", resp.StatusCode)
+	}
+
+	var feed atomFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("arxiv: parse: %w", err)
+	}
+	return feed.Entries, nil
+}
+
+// watchedAuthors returns the watched names among entry's authors. A name
+// matches an author whose name contains all its words, so "Andrew Pavlo"
+// matches "Andrew J. Pavlo".
+func (s *Source) watchedAuthors(entry atomEntry) []string {
+	var found []string
+	for _, watch := range s.authors {
+		want := strings.Fields(strings.ToLower(strings.ReplaceAll(watch, ".", " ")))
+		if len(want) == 0 {
+			continue
+		}
+		for _, a := range entry.Authors {
+			have := map[string]bool{}
+			for _, w := range strings.Fields(strings.ToLower(strings.ReplaceAll(a.Name, ".", " "))) {
+				have[w] = true
+			}
+			all := true
+			for _, w := range want {
+				if !have[w] {
+					all = false
+					break
+				}
+			}
+			if all {
+				found = append(found, watch)
+				break
+			}
+		}
+	}
+	return found
+}
+
+func publishedAt(entry atomEntry) time.Time {
+	published, _ := time.Parse(time.RFC3339, entry.Published)
+	if published.IsZero() {
+		published, _ = time.Parse(time.RFC3339, entry.Updated)
+	}
+	return published
+}
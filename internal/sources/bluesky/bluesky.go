@@ -0,0 +1,405 @@
+// Package bluesky provides a Bluesky source using the public AppView XRPC API.
+package bluesky
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// DefaultAppView serves public, unauthenticated reads.
+const DefaultAppView = "https://public.api.bsky.app"
+
+// feedResponse is the shape of getAuthorFeed and getFeed responses.
+type feedResponse struct {
+	Feed []struct {
+		Post   Post `json:"post"`
+		Reason *struct {
+			Type string `json:"$type"`
+		} `json:"reason"`
+	} `json:"feed"`
+}
+
+// searchResponse is the shape of searchPosts responses.
+type searchResponse struct {
+	Posts []Post `json:"posts"`
+}
+
+// Post represents a post view
+type Post struct {
+	URI         string `json:"uri"`
+	CID         string `json:"cid"`
+	Author      Author `json:"author"`
+	Record      Record `json:"record"`
+	Embed       *Embed `json:"embed"`
+	ReplyCount  int    `json:"replyCount"`
+	RepostCount int    `json:"repostCount"`
+	LikeCount   int    `json:"likeCount"`
+	QuoteCount  int    `json:"quoteCount"`
+	IndexedAt   string `json:"indexedAt"`
+}
+
+// Author is the profile that wrote a post
+type Author struct {
+	DID         string `json:"did"`
+	Handle      string `json:"handle"`
+	DisplayName string `json:"displayName"`
+}
+
+// Record is the post record itself
+type Record struct {
+	Text      string   `json:"text"`
+	CreatedAt string   `json:"createdAt"`
+	Langs     []string `json:"langs"`
+	Tags      []string `json:"tags"`
+	Reply     *struct {
+		Parent struct {
+			URI string `json:"uri"`
+		} `json:"parent"`
+	} `json:"reply"`
+	Facets []struct {
+		Features []struct {
+			Type string `json:"$type"`
+			URI  string `json:"uri"`
+			Tag  string `json:"tag"`
+		} `json:"features"`
+	} `json:"facets"`
+}
+
+// Embed is an embed view. Links are "external"; a quote post with a link
+// carries it in Media.
+type Embed struct {
+	Type     string    `json:"$type"`
+	External *External `json:"external"`
+	Media    *Embed    `json:"media"`
+}
+
+// External is a link card
+type External struct {
+	URI         string `json:"uri"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Source reads author feeds, custom feeds and post searches from Bluesky.
+//
+// Settings:
+//
+//	max         number of posts (default 10)
+//	links_only  skip posts that don't link anywhere (default false)
+//	min_likes   skip posts with fewer likes than this (default 0)
+type Source struct {
+	name    string
+	icon    string
+	handles []string // e.g. "jay.bsky.team"
+	feeds   []string // feed generator AT-URIs or bsky.app feed URLs
+	queries []string // searchPosts queries
+}
+
+// New creates a Bluesky source.
+func New(name string, handles, feeds, queries []string, icon string) *Source {
+	if icon == "" {
+		icon = "🦋"
+	}
+	return &Source{
+		name:    name,
+		icon:    icon,
+		handles: handles,
+		feeds:   feeds,
+		queries: queries,
+	}
+}
+
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "bluesky",
+		Help: "Bluesky author feeds, custom feeds and post searches",
+		Fields: []source.Field{
+			{Name: "handles", Type: "strings", Help: "accounts whose posts to read"},
+			{Name: "feeds", Type: "strings", Help: "feed generator AT-URIs or bsky.app feed URLs"},
+			{Name: "queries", Type: "strings", Help: "post search queries"},
+			source.MaxField(10),
+			{Name: "links_only", Type: "bool", Setting: true, Help: "skip posts without a link (default false)"},
+			{Name: "min_likes", Type: "int", Setting: true, Help: "skip posts with fewer likes (default 0)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if len(spec.Handles)+len(spec.Feeds)+len(spec.Queries) == 0 {
+				return nil, errors.New("one of handles, feeds or queries is required")
+			}
+			return New(spec.Name, spec.Handles, spec.Feeds, spec.Queries, spec.Icon), nil
+		},
+	})
+}
+
+func (s *Source) Name() string       { return s.name }
+func (s *Source) Icon() string       { return s.icon }
+func (s *Source) TTL() time.Duration { return 15 * time.Minute }
+
+func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	maxItems := cfg.Int("max", 10)
+	linksOnly := cfg.Bool("links_only", false)
+	minLikes := cfg.Int("min_likes", 0)
+	limit := min(maxItems*2, 100)
+
+	type request struct {
+		label  string
+		method string
+		params url.Values
+	}
+	var reqs []request
+	for _, h := range s.handles {
+		h = strings.TrimPrefix(h, "@")
+		reqs = append(reqs, request{"@" + h, "app.bsky.feed.getAuthorFeed", url.Values{
+			"actor":  {h},
+			"filter": {"posts_no_replies"},
+		}})
+	}
+	for _, f := range s.feeds {
+		uri, err := feedURI(f)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, request{"feed " + uri[strings.LastIndex(uri, "/")+1:], "app.bsky.feed.getFeed", url.Values{
+			"feed": {uri},
+		}})
+	}
+	for _, q := range s.queries {
+		reqs = append(reqs, request{"“" + q + "”", "app.bsky.feed.searchPosts", url.Values{
+			"q":    {q},
+			"sort": {"top"},
+		}})
+	}
+	if len(reqs) == 0 {
+		return nil, errors.New("bluesky: no handles, feeds or queries configured")
+	}
+
+	seen := map[string]bool{}
+	var items []source.Item
+	var errs []error
+	for _, r := range reqs {
+		r.params.Set("limit", fmt.Sprintf("%d", limit))
+		posts, err := fetchPosts(ctx, r.method, r.params)
+		switch {
+		case errors.Is(err, source.ErrNotModified):
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", r.label, err))
+			continue
+		}
+		for _, p := range posts {
+			if seen[p.URI] || p.Record.Reply != nil {
+				continue
+			}
+			if p.LikeCount < minLikes {
+				continue
+			}
+			link := externalURL(p)
+			if linksOnly && link == "" {
+				continue
+			}
+			seen[p.URI] = true
+			items = append(items, s.toItem(p, link, r.label))
+		}
+	}
+
+	// Only fail the sync when nothing could be read.
+	if len(errs) > 0 && len(items) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp.After(items[j].Timestamp)
+	})
+	if len(items) > maxItems {
+		items = items[:maxItems]
+	}
+
+	return &source.Section{
+		Name:     s.name,
+		Icon:     s.icon,
+		Priority: 45,
+		Items:    items,
+	}, nil
+}
+
+func (s *Source) toItem(p Post, link, label string) source.Item {
+	timestamp, _ := time.Parse(time.RFC3339, p.Record.CreatedAt)
+	if timestamp.IsZero() {
+		timestamp, _ = time.Parse(time.RFC3339, p.IndexedAt)
+	}
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	text := sanitize.Text(strings.TrimSpace(p.Record.Text))
+	postURL := webURL(p)
+
+	// Cross-posted links should dedupe against the same link elsewhere, so
+	// the linked page is the item and the post is the discussion.
+	itemURL := postURL
+	title := sanitize.FirstLine(text, 100)
+	if link != "" {
+		itemURL = link
+		if ext := external(p.Embed); ext != nil && strings.TrimSpace(ext.Title) != "" {
+			title = sanitize.Text(strings.TrimSpace(ext.Title))
+		}
+	}
+	if title == "" {
+		title = "Post by @" + p.Author.Handle
+	}
+
+	// Reposts and quotes spread a post further than likes do.
+	points := p.LikeCount + 2*(p.RepostCount+p.QuoteCount)
+	priority := source.Low
+	switch {
+	case points >= 500:
+		priority = source.Urgent
+	case points >= 100:
+		priority = source.High
+	case points >= 20:
+		priority = source.Medium
+	}
+
+	tags := make([]string, 0, len(p.Record.Tags))
+	for _, t := range p.Record.Tags {
+		tags = append(tags, strings.ToLower(t))
+	}
+	for _, f := range p.Record.Facets {
+		for _, feat := range f.Features {
+			if feat.Type == "app.bsky.richtext.facet#tag" && feat.Tag != "" {
+				tags = append(tags, strings.ToLower(feat.Tag))
+			}
+		}
+	}
+
+	return source.Item{
+		ID:        "bsky-" + p.CID,
+		Title:     title,
+		Subtitle:  fmt.Sprintf("@%s • %s", p.Author.Handle, label),
+		Body:      text,
+		URL:       itemURL,
+		Timestamp: timestamp,
+		Priority:  priority,
+		Category:  "discussion",
+		Icon:      s.icon,
+		Actions: []source.Action{
+			{Key: "o", Label: "open", Command: itemURL},
+			{Key: "c", Label: "thread", Command: postURL},
+		},
+		Metadata: map[string]any{
+			"points":       points,
+			"comments":     p.ReplyCount,
+			"likes":        p.LikeCount,
+			"reposts":      p.RepostCount + p.QuoteCount,
+			"author":       "@" + p.Author.Handle,
+			"tags":         tags,
+			"comments_url": postURL,
+		},
+	}
+}
+
+func fetchPosts(ctx context.Context, method string, params url.Values) ([]Post, error) {
+	reqURL := appView() + "/xrpc/" + method + "?" + params.Encode()
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+
+	resp, err := source.DefaultClient.Get(ctx, reqURL, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bluesky: %s returned %d", method, resp.StatusCode)
+	}
+
+	if method == "app.bsky.feed.searchPosts" {
+		var result searchResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, err
+		}
+		return result.Posts, nil
+	}
+
+	var result feedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	posts := make([]Post, 0, len(result.Feed))
+	for _, entry := range result.Feed {
+		posts = append(posts, entry.Post)
+	}
+	return posts, nil
+}
+
+// appView returns the XRPC host. BLUESKY_APPVIEW overrides it, e.g. to
+// point at recorded fixtures.
+func appView() string {
+	if u := os.Getenv("BLUESKY_APPVIEW"); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	return DefaultAppView
+}
+
+// feedURI accepts a feed generator AT-URI or its bsky.app page URL,
+// https://bsky.app/profile/<actor>/feed/<rkey>, and returns the AT-URI.
+func feedURI(f string) (string, error) {
+	if strings.HasPrefix(f, "at://") {
+		return f, nil
+	}
+	u, err := url.Parse(f)
+	if err == nil {
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) == 4 && parts[0] == "profile" && parts[2] == "feed" {
+			return fmt.Sprintf("at://%s/app.bsky.feed.generator/%s", parts[1], parts[3]), nil
+		}
+	}
+	return "", fmt.Errorf("bluesky: %q is not a feed AT-URI or bsky.app feed URL", f)
+}
+
+// externalURL returns the link a post shares: its link card, or failing
+// that the first link in its text.
+func externalURL(p Post) string {
+	if ext := external(p.Embed); ext != nil && ext.URI != "" {
+		return ext.URI
+	}
+	for _, f := range p.Record.Facets {
+		for _, feat := range f.Features {
+			if feat.Type == "app.bsky.richtext.facet#link" && feat.URI != "" {
+				return feat.URI
+			}
+		}
+	}
+	return ""
+}
+
+func external(e *Embed) *External {
+	if e == nil {
+		return nil
+	}
+	if e.External != nil {
+		return e.External
+	}
+	return external(e.Media)
+}
+
+// webURL returns the bsky.app page for a post.
+func webURL(p Post) string {
+	rkey := p.URI[strings.LastIndex(p.URI, "/")+1:]
+	actor := p.Author.Handle
+	if actor == "" {
+		actor = p.Author.DID
+	}
+	return fmt.Sprintf("https://bsky.app/profile/%s/post/%s", actor, rkey)
+}
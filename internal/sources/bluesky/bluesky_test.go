@@ -0,0 +1,98 @@
+package bluesky
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// appViewServer serves testdata/author_feed.json as the feed of
+// alice.bsky.social. Other actors are rejected the way the AppView does.
+func appViewServer(t *testing.T) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/xrpc/app.bsky.feed.getAuthorFeed" || r.URL.Query().Get("actor") != "alice.bsky.social" {
+			http.Error(w, `{"error":"InvalidRequest"}`, http.StatusBadRequest)
+			return
+		}
+		if got := r.URL.Query().Get("filter"); got != "posts_no_replies" {
+			t.Errorf("filter = %q", got)
+		}
+		http.ServeFile(w, r, "testdata/author_feed.json")
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("BLUESKY_APPVIEW", srv.URL+"/")
+}
+
+func TestFetchAuthorFeed(t *testing.T) {
+	appViewServer(t)
+	src := New("Bluesky", []string{"@alice.bsky.social"}, nil, nil, "")
+
+	section, err := src.Fetch(context.Background(), source.Config{})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	// The reply is dropped even though the AppView returned it.
+	if len(section.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(section.Items))
+	}
+
+	// A post sharing a link becomes the linked page, titled by its card.
+	got := section.Items[0]
+	if got.ID != "bsky-cid-link" || got.Title != "Why SQLite is enough" || got.URL != "https://example.com/sqlite" {
+		t.Errorf("item = %q, %q, %q", got.ID, got.Title, got.URL)
+	}
+	if got.Subtitle != "@alice.bsky.social • @alice.bsky.social" || got.Priority != source.High {
+		t.Errorf("item = %q, priority %v", got.Subtitle, got.Priority)
+	}
+	if want := "https://bsky.app/profile/alice.bsky.social/post/3klink"; got.Metadata["comments_url"] != want {
+		t.Errorf("comments_url = %v, want %q", got.Metadata["comments_url"], want)
+	}
+	if got.Metadata["points"] != 180 {
+		t.Errorf("points = %v, want 180", got.Metadata["points"])
+	}
+	if tags, _ := got.Metadata["tags"].([]string); len(tags) != 2 || tags[0] != "sqlite" || tags[1] != "databases" {
+		t.Errorf("tags = %v", got.Metadata["tags"])
+	}
+
+	// A post without a link is its own item, titled by its first line.
+	got = section.Items[1]
+	if got.Title != "Shipping day!" || got.URL != "https://bsky.app/profile/alice.bsky.social/post/3ktext" {
+		t.Errorf("item = %q, %q", got.Title, got.URL)
+	}
+	if want := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC); !got.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", got.Timestamp, want)
+	}
+}
+
+func TestFetchFilters(t *testing.T) {
+	appViewServer(t)
+	src := New("Bluesky", []string{"alice.bsky.social"}, nil, nil, "")
+
+	for _, settings := range []map[string]any{
+		{"links_only": true},
+		{"min_likes": 100},
+		{"max": 1},
+	} {
+		section, err := src.Fetch(context.Background(), source.Config{Settings: settings})
+		if err != nil {
+			t.Fatalf("%v: Fetch: %v", settings, err)
+		}
+		if len(section.Items) != 1 || section.Items[0].ID != "bsky-cid-link" {
+			t.Errorf("%v: got %v", settings, section.Items)
+		}
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	appViewServer(t)
+	src := New("Bluesky", []string{"nobody.example"}, nil, nil, "")
+
+	if _, err := src.Fetch(context.Background(), source.Config{}); err == nil {
+		t.Fatal("Fetch succeeded for an unknown actor")
+	}
+}
@@ -0,0 +1,243 @@
+// Package execsource provides a source that runs a local command and reads
+// TRSS NDJSON items from its stdout.
+//
+// The command receives the source's settings as a JSON object on stdin and
+// writes one trss.Item per line. A non-zero exit fails the fetch, with the
+// end of stderr in the error.
+package execsource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+	"github.com/jcornudella/hotbrew/pkg/trss"
+)
+
+// maxStderr caps how much of the command's stderr goes into an error.
+const maxStderr = 2000
+
+// waitDelay bounds how long Fetch waits for the command's output to close
+// once it has been killed.
+const waitDelay = 2 * time.Second
+
+// Source runs a command and turns its NDJSON output into items.
+//
+// Settings:
+//
+//	max      number of items (default 20)
+//	timeout  how long the command may run, e.g. "10s" (default 30s)
+//	ttl      how long results stay fresh, e.g. "5m" (default 15m)
+//
+// All settings, including these, are written to the command's stdin.
+type Source struct {
+	name string
+	icon string
+	argv []string
+	ttl  time.Duration
+}
+
+// New creates a source that runs argv. A leading "~/" in the program path
+// is expanded to the home directory.
+func New(name string, argv []string, icon string, ttl time.Duration) *Source {
+	if icon == "" {
+		icon = "⚙️"
+	}
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+	return &Source{
+		name: name,
+		icon: icon,
+		argv: argv,
+		ttl:  ttl,
+	}
+}
+
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "exec",
+		Help: "items printed as TRSS NDJSON by a local command",
+		Fields: []source.Field{
+			{Name: "command", Type: "strings", Required: true, Help: "program and arguments"},
+			source.MaxField(20),
+			{Name: "timeout", Type: "duration", Setting: true, Help: "how long the command may run (default 30s)"},
+			{Name: "ttl", Type: "duration", Setting: true, Help: "how long results stay fresh (default 15m)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if len(spec.Command) == 0 {
+				return nil, errors.New("command is required")
+			}
+			var ttl time.Duration
+			if v := (source.Config{Settings: spec.Settings}).String("ttl", ""); v != "" {
+				d, err := time.ParseDuration(v)
+				if err != nil {
+					return nil, fmt.Errorf("bad ttl %q: %w", v, err)
+				}
+				ttl = d
+			}
+			return New(spec.Name, spec.Command, spec.Icon, ttl), nil
+		},
+	})
+}
+
+func (s *Source) Name() string       { return s.name }
+func (s *Source) Icon() string       { return s.icon }
+func (s *Source) TTL() time.Duration { return s.ttl }
+
+func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	if len(s.argv) == 0 {
+		return nil, errors.New("exec: no command configured")
+	}
+	maxItems := cfg.Int("max", 20)
+	timeout := 30 * time.Second
+	if v := cfg.String("timeout", ""); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("exec: bad timeout %q: %w", v, err)
+		}
+		timeout = d
+	}
+
+	settings := cfg.Settings
+	if settings == nil {
+		settings = map[string]any{}
+	}
+	input, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("exec: encode settings: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, expandHome(s.argv[0]), s.argv[1:]...)
+	killGroup(cmd)
+	// Stop waiting for the output pipes shortly after the kill, in case a
+	// process outside the group still holds them.
+	cmd.WaitDelay = waitDelay
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		if msg := tail(stderr.String(), maxStderr); msg != "" {
+			return nil, fmt.Errorf("exec %s: %w: %s", filepath.Base(s.argv[0]), err, msg)
+		}
+		return nil, fmt.Errorf("exec %s: %w", filepath.Base(s.argv[0]), err)
+	}
+
+	decoded, err := trss.DecodeItems(&stdout)
+	if err != nil {
+		return nil, fmt.Errorf("exec %s: read output: %w", filepath.Base(s.argv[0]), err)
+	}
+
+	items := make([]source.Item, 0, min(len(decoded), maxItems))
+	for _, it := range decoded {
+		if len(items) >= maxItems {
+			break
+		}
+		if it.Title == "" {
+			continue
+		}
+		items = append(items, s.toItem(it))
+	}
+
+	return &source.Section{
+		Name:     s.name,
+		Icon:     s.icon,
+		Priority: 40,
+		Items:    items,
+	}, nil
+}
+
+// toItem maps a TRSS item back to a source item. Engagement and meta are
+// merged into Metadata so sync carries them through; meta["category"]
+// sets the category, and the score sets the priority.
+func (s *Source) toItem(it trss.Item) source.Item {
+	timestamp := it.PublishedAt
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	meta := make(map[string]any, len(it.Meta)+len(it.Engagement)+1)
+	for k, v := range it.Meta {
+		meta[k] = v
+	}
+	for k, v := range it.Engagement {
+		meta[k] = v
+	}
+	if len(it.Tags) > 0 {
+		meta["tags"] = it.Tags
+	}
+
+	category, _ := meta["category"].(string)
+	if category == "" {
+		category = "exec"
+	}
+
+	priority := source.Low
+	switch {
+	case it.Score >= 9:
+		priority = source.Urgent
+	case it.Score >= 7:
+		priority = source.High
+	case it.Score >= 5:
+		priority = source.Medium
+	}
+
+	id := it.ID
+	if id == "" {
+		id = it.URL
+	}
+
+	var actions []source.Action
+	if it.URL != "" {
+		actions = []source.Action{{Key: "o", Label: "open", Command: it.URL}}
+	}
+
+	return source.Item{
+		ID:        id,
+		Title:     sanitize.Text(it.Title),
+		Subtitle:  sanitize.Text(it.Summary),
+		Body:      sanitize.Text(it.Body),
+		URL:       it.URL,
+		Timestamp: timestamp,
+		Priority:  priority,
+		Category:  category,
+		Icon:      s.icon,
+		Actions:   actions,
+		Metadata:  meta,
+	}
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, rest)
+	}
+	return path
+}
+
+// tail returns the last n runes of s, trimmed.
+func tail(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if r := []rune(s); len(r) > n {
+		s = "…" + string(r[len(r)-n:])
+	}
+	return s
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package execsource
+
+import "os/exec"
+
+// killGroup leaves cmd as it is: without process groups, cancelling kills
+// only the command itself.
+func killGroup(cmd *exec.Cmd) {}
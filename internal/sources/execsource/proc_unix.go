@@ -0,0 +1,18 @@
+//go:build unix
+
+package execsource
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killGroup starts cmd in its own process group and makes cancelling it
+// kill the whole group, so children the command spawned don't outlive it
+// holding its stdout open.
+func killGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
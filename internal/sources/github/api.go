@@ -0,0 +1,31 @@
+package github
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+const defaultAPIURL = "https://api.github.com"
+
+// apiURL returns the REST API base URL. GITHUB_API_URL overrides it for
+// GitHub Enterprise, or to point the drivers at recorded fixtures.
+func apiURL() string {
+	if u := os.Getenv("GITHUB_API_URL"); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	return defaultAPIURL
+}
+
+// apiHeader returns the headers for a REST API request, authenticated with
+// GITHUB_TOKEN when it is set. Unauthenticated requests are limited to 60
+// an hour.
+func apiHeader() http.Header {
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return header
+}
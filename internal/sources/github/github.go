@@ -1,20 +1,18 @@
-// Package github provides a GitHub trending source for digest
+// Package github provides GitHub trending, release and issue sources for digest
 package github
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/jcornudella/hotbrew/pkg/profile"
 	"github.com/jcornudella/hotbrew/pkg/source"
 )
 
-const searchURL = "https://api.github.com/search/repositories"
-
 // SearchResult represents the GitHub search response
 type SearchResult struct {
 	Items []Repo `json:"items"`
@@ -61,6 +59,20 @@ func New(name string, topics []string, icon string) *Source {
 	}
 }
 
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "github-trending",
+		Help: "recently active GitHub repositories by topic",
+		Fields: []source.Field{
+			{Name: "topics", Type: "strings", Help: "repository topics to match (default any)"},
+			source.MaxField(8),
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			return New(spec.Name, spec.Topics, spec.Icon), nil
+		},
+	})
+}
+
 func (s *Source) Name() string       { return s.name }
 func (s *Source) Icon() string       { return s.icon }
 func (s *Source) TTL() time.Duration { return 30 * time.Minute }
@@ -163,17 +175,10 @@ func searchRepos(ctx context.Context, query string, limit int) ([]Repo, error) {
 	params.Set("order", "desc")
 	params.Set("per_page", fmt.Sprintf("%d", limit))
 
-	reqURL := searchURL + "?" + params.Encode()
-	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// GitHub API requires User-Agent
-	req.Header.Set("User-Agent", "hotbrew-cli")
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	reqURL := apiURL() + "/search/repositories?" + params.Encode()
 
-	resp, err := http.DefaultClient.Do(req)
+	// Conditional requests that return 304 don't count against the rate limit.
+	resp, err := source.DefaultClient.Get(ctx, reqURL, apiHeader())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,405 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// Filters maps the filter names accepted in settings to search qualifiers.
+var Filters = map[string]string{
+	"assigned":         "assignee:@me",
+	"review-requested": "review-requested:@me",
+	"mentioned":        "mentions:@me",
+	"authored":         "author:@me",
+}
+
+// IssueSearchResult represents the GitHub issue search response
+type IssueSearchResult struct {
+	Items []Issue `json:"items"`
+}
+
+// Issue represents a GitHub issue or pull request
+type Issue struct {
+	ID          int64   `json:"id"`
+	Number      int     `json:"number"`
+	Title       string  `json:"title"`
+	Body        string  `json:"body"`
+	HTMLURL     string  `json:"html_url"`
+	RepoURL     string  `json:"repository_url"`
+	State       string  `json:"state"`
+	Comments    int     `json:"comments"`
+	UpdatedAt   string  `json:"updated_at"`
+	User        Owner   `json:"user"`
+	Labels      []Label `json:"labels"`
+	Draft       bool    `json:"draft"`
+	PullRequest *struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"pull_request"`
+}
+
+// Label represents an issue label
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Notification represents a GitHub notification thread
+type Notification struct {
+	ID         string `json:"id"`
+	Reason     string `json:"reason"`
+	UpdatedAt  string `json:"updated_at"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Subject struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		Type  string `json:"type"`
+	} `json:"subject"`
+}
+
+// IssuesSource surfaces open issues and pull requests that need the user.
+// It needs GITHUB_TOKEN, since the filters are relative to the token's user.
+//
+// Settings:
+//
+//	max            number of items (default 20)
+//	filters        assigned, review-requested, mentioned, authored
+//	               (default assigned, review-requested)
+//	labels         only include items with all of these labels
+//	notifications  also include unread participating notifications (default false)
+type IssuesSource struct {
+	name    string
+	repos   []string // "owner/repo"; empty means every repo the user can see
+	queries []string // extra search qualifiers, e.g. "is:pr" or "org:acme"
+	icon    string
+}
+
+// NewIssues creates a source for the issues and pull requests waiting on the
+// user in repos. Each query adds qualifiers to every search.
+func NewIssues(name string, repos, queries []string, icon string) *IssuesSource {
+	if icon == "" {
+		icon = "📋"
+	}
+	return &IssuesSource{
+		name:    name,
+		repos:   repos,
+		queries: queries,
+		icon:    icon,
+	}
+}
+
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "github-issues",
+		Help: "GitHub issues and pull requests waiting on you (needs GITHUB_TOKEN)",
+		Fields: []source.Field{
+			{Name: "repos", Type: "strings", Help: "repositories as owner/repo (default all)"},
+			{Name: "queries", Type: "strings", Help: "extra search qualifiers, e.g. is:pr"},
+			source.MaxField(20),
+			{Name: "filters", Type: "strings", Setting: true, Help: "assigned, review-requested, mentioned, authored (default assigned, review-requested)"},
+			{Name: "labels", Type: "strings", Setting: true, Help: "only items with all of these labels"},
+			{Name: "notifications", Type: "bool", Setting: true, Help: "include participating notifications (default false)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			return NewIssues(spec.Name, spec.Repos, spec.Queries, spec.Icon), nil
+		},
+	})
+}
+
+func (s *IssuesSource) Name() string       { return s.name }
+func (s *IssuesSource) Icon() string       { return s.icon }
+func (s *IssuesSource) TTL() time.Duration { return 5 * time.Minute }
+
+func (s *IssuesSource) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	if os.Getenv("GITHUB_TOKEN") == "" {
+		return nil, errors.New("github-issues: GITHUB_TOKEN is not set")
+	}
+
+	maxItems := cfg.Int("max", 20)
+	labels := cfg.Strings("labels")
+	filters := cfg.Strings("filters")
+	if len(filters) == 0 {
+		filters = []string{"assigned", "review-requested"}
+	}
+	for _, f := range filters {
+		if _, ok := Filters[f]; !ok {
+			return nil, fmt.Errorf("github-issues: unknown filter %q (want assigned, review-requested, mentioned or authored)", f)
+		}
+	}
+
+	// One search per filter; an item matching several keeps every reason.
+	seen := make(map[int64]int)
+	var items []source.Item
+	var errs []error
+	for _, f := range filters {
+		issues, err := searchIssues(ctx, s.query(Filters[f], labels), maxItems)
+		switch {
+		case errors.Is(err, source.ErrNotModified):
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", f, err))
+			continue
+		}
+		for _, issue := range issues {
+			if i, ok := seen[issue.ID]; ok {
+				s.addReason(&items[i], f)
+				continue
+			}
+			seen[issue.ID] = len(items)
+			items = append(items, s.issueItem(issue, f))
+		}
+	}
+
+	if cfg.Bool("notifications", false) {
+		notes, err := fetchNotifications(ctx)
+		switch {
+		case errors.Is(err, source.ErrNotModified):
+		case err != nil:
+			errs = append(errs, fmt.Errorf("notifications: %w", err))
+		default:
+			items = append(items, s.notificationItems(notes, items)...)
+		}
+	}
+
+	// Only fail the sync when nothing could be read.
+	if len(errs) > 0 && len(items) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Priority != items[j].Priority {
+			return items[i].Priority > items[j].Priority
+		}
+		return items[i].Timestamp.After(items[j].Timestamp)
+	})
+	if len(items) > maxItems {
+		items = items[:maxItems]
+	}
+
+	return &source.Section{
+		Name:     s.name,
+		Icon:     s.icon,
+		Priority: 40,
+		Items:    items,
+	}, nil
+}
+
+// query builds the search query for one filter qualifier.
+func (s *IssuesSource) query(filter string, labels []string) string {
+	parts := []string{"is:open", "archived:false", filter}
+	for _, repo := range s.repos {
+		parts = append(parts, "repo:"+repo)
+	}
+	for _, l := range labels {
+		parts = append(parts, fmt.Sprintf("label:%q", l))
+	}
+	parts = append(parts, s.queries...)
+	return strings.Join(parts, " ")
+}
+
+func (s *IssuesSource) issueItem(issue Issue, filter string) source.Item {
+	timestamp, _ := time.Parse(time.RFC3339, issue.UpdatedAt)
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	repo := repoFromAPIURL(issue.RepoURL)
+	isPR := issue.PullRequest != nil
+
+	kind, category := "issue", "issue"
+	actions := []source.Action{
+		{Key: "o", Label: "open issue", Command: issue.HTMLURL},
+	}
+	if isPR {
+		kind, category = "PR", "pr"
+		actions = []source.Action{
+			{Key: "o", Label: "open PR", Command: issue.HTMLURL},
+			{Key: "a", Label: "approve", Command: fmt.Sprintf("gh pr review %d --approve -R %s", issue.Number, repo)},
+			{Key: "c", Label: "checkout", Command: fmt.Sprintf("gh pr checkout %d -R %s", issue.Number, repo)},
+		}
+	}
+
+	labels := make([]string, 0, len(issue.Labels))
+	for _, l := range issue.Labels {
+		labels = append(labels, l.Name)
+	}
+
+	subtitle := fmt.Sprintf("%s#%d %s by %s", repo, issue.Number, kind, issue.User.Login)
+	if issue.Draft {
+		subtitle += " (draft)"
+	}
+
+	body := strings.TrimSpace(issue.Body)
+	if r := []rune(body); len(r) > maxNotes {
+		body = string(r[:maxNotes]) + "\n…"
+	}
+
+	item := source.Item{
+		ID:        fmt.Sprintf("gh-issue-%d", issue.ID),
+		Title:     issue.Title,
+		Subtitle:  subtitle,
+		Body:      body,
+		URL:       issue.HTMLURL,
+		Timestamp: timestamp,
+		Category:  category,
+		Icon:      s.icon,
+		Actions:   actions,
+		Metadata: map[string]any{
+			"repo":     repo,
+			"number":   issue.Number,
+			"author":   issue.User.Login,
+			"comments": issue.Comments,
+			"labels":   labels,
+			"draft":    issue.Draft,
+			"reasons":  []string{},
+		},
+	}
+	s.addReason(&item, filter)
+	return item
+}
+
+// addReason records why item matched and raises its priority to match.
+// Review requests block someone else, so they rank above assignments.
+func (s *IssuesSource) addReason(item *source.Item, reason string) {
+	reasons, _ := item.Metadata["reasons"].([]string)
+	item.Metadata["reasons"] = append(reasons, reason)
+
+	priority := source.Low
+	switch reason {
+	case "review-requested", "review_requested":
+		priority = source.High
+	case "assigned", "assign":
+		priority = source.Medium
+	}
+	if priority > item.Priority {
+		item.Priority = priority
+	}
+}
+
+// notificationItems converts notification threads, skipping those already
+// covered by a search result and those outside the source's repos.
+func (s *IssuesSource) notificationItems(notes []Notification, existing []source.Item) []source.Item {
+	have := make(map[string]bool, len(existing))
+	for _, item := range existing {
+		have[item.URL] = true
+	}
+	repos := make(map[string]bool, len(s.repos))
+	for _, r := range s.repos {
+		repos[strings.ToLower(r)] = true
+	}
+
+	var items []source.Item
+	for _, n := range notes {
+		repo := n.Repository.FullName
+		if len(repos) > 0 && !repos[strings.ToLower(repo)] {
+			continue
+		}
+		link := htmlURL(n.Subject.URL)
+		if link == "" {
+			link = "https://github.com/" + repo
+		}
+		if have[link] {
+			continue
+		}
+
+		timestamp, _ := time.Parse(time.RFC3339, n.UpdatedAt)
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+
+		item := source.Item{
+			ID:        "gh-notification-" + n.ID,
+			Title:     n.Subject.Title,
+			Subtitle:  fmt.Sprintf("%s • %s (%s)", repo, n.Subject.Type, strings.ReplaceAll(n.Reason, "_", " ")),
+			URL:       link,
+			Timestamp: timestamp,
+			Category:  "notification",
+			Icon:      s.icon,
+			Actions: []source.Action{
+				{Key: "o", Label: "open", Command: link},
+			},
+			Metadata: map[string]any{
+				"repo":    repo,
+				"type":    n.Subject.Type,
+				"reasons": []string{},
+			},
+		}
+		s.addReason(&item, n.Reason)
+		items = append(items, item)
+	}
+	return items
+}
+
+func searchIssues(ctx context.Context, query string, limit int) ([]Issue, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("sort", "updated")
+	params.Set("order", "desc")
+	params.Set("per_page", fmt.Sprintf("%d", min(limit, 100)))
+
+	reqURL := apiURL() + "/search/issues?" + params.Encode()
+	resp, err := source.DefaultClient.Get(ctx, reqURL, apiHeader())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned %d", resp.StatusCode)
+	}
+
+	var result IssueSearchResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+func fetchNotifications(ctx context.Context) ([]Notification, error) {
+	reqURL := apiURL() + "/notifications?participating=true&per_page=50"
+	resp, err := source.DefaultClient.Get(ctx, reqURL, apiHeader())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned %d", resp.StatusCode)
+	}
+
+	var notes []Notification
+	if err := json.NewDecoder(resp.Body).Decode(&notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// repoFromAPIURL returns the path after /repos/ in an API URL, which is
+// "owner/repo" for a repository URL.
+func repoFromAPIURL(u string) string {
+	_, repo, ok := strings.Cut(u, "/repos/")
+	if !ok {
+		return ""
+	}
+	return repo
+}
+
+// htmlURL maps an issue or pull request API URL to its web page.
+func htmlURL(apiLink string) string {
+	path := repoFromAPIURL(apiLink) // "owner/name/issues/1" or "owner/name/pulls/1"
+	if strings.Count(path, "/") < 2 {
+		return ""
+	}
+	return "https://github.com/" + strings.Replace(path, "/pulls/", "/pull/", 1)
+}
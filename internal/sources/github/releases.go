@@ -0,0 +1,217 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// maxNotes caps the release notes kept in an item body.
+const maxNotes = 4000
+
+// Release represents a GitHub release
+type Release struct {
+	ID          int64  `json:"id"`
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	HTMLURL     string `json:"html_url"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	PublishedAt string `json:"published_at"`
+	Author      Owner  `json:"author"`
+}
+
+// ReleasesSource follows the releases of specific repositories.
+//
+// Settings:
+//
+//	max          releases per repository (default 3)
+//	prereleases  include prereleases (default true)
+type ReleasesSource struct {
+	name  string
+	repos []string // "owner/repo"
+	icon  string
+}
+
+// NewReleases creates a source for the releases of repos, given as "owner/repo".
+func NewReleases(name string, repos []string, icon string) *ReleasesSource {
+	if icon == "" {
+		icon = "🏷️"
+	}
+	return &ReleasesSource{
+		name:  name,
+		repos: repos,
+		icon:  icon,
+	}
+}
+
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "github-releases",
+		Help: "new releases of specific GitHub repositories",
+		Fields: []source.Field{
+			{Name: "repos", Type: "strings", Required: true, Help: "repositories as owner/repo"},
+			{Name: "max", Type: "int", Setting: true, Help: "releases per repository (default 3)"},
+			{Name: "prereleases", Type: "bool", Setting: true, Help: "include prereleases (default true)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if len(spec.Repos) == 0 {
+				return nil, errors.New("repos is required")
+			}
+			return NewReleases(spec.Name, spec.Repos, spec.Icon), nil
+		},
+	})
+}
+
+func (s *ReleasesSource) Name() string       { return s.name }
+func (s *ReleasesSource) Icon() string       { return s.icon }
+func (s *ReleasesSource) TTL() time.Duration { return time.Hour }
+
+func (s *ReleasesSource) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	perRepo := cfg.Int("max", 3)
+	prereleases := cfg.Bool("prereleases", true)
+
+	type result struct {
+		releases []Release
+		err      error
+	}
+	results := make([]result, len(s.repos))
+	var wg sync.WaitGroup
+	for i, repo := range s.repos {
+		wg.Add(1)
+		go func(i int, repo string) {
+			defer wg.Done()
+			releases, err := fetchReleases(ctx, repo, perRepo)
+			results[i] = result{releases, err}
+		}(i, repo)
+	}
+	wg.Wait()
+
+	var items []source.Item
+	var errs []error
+	for i, r := range results {
+		repo := s.repos[i]
+		switch {
+		case errors.Is(r.err, source.ErrNotModified):
+			// No new releases since the last sync.
+			continue
+		case r.err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", repo, r.err))
+			continue
+		}
+
+		n := 0
+		for _, rel := range r.releases {
+			if rel.Draft || (rel.Prerelease && !prereleases) {
+				continue
+			}
+			if n == perRepo {
+				break
+			}
+			items = append(items, s.toItem(repo, rel))
+			n++
+		}
+	}
+
+	// Only fail the sync when no repository could be read.
+	if len(errs) > 0 && len(errs) == len(s.repos) {
+		return nil, errors.Join(errs...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp.After(items[j].Timestamp)
+	})
+
+	return &source.Section{
+		Name:     s.name,
+		Icon:     s.icon,
+		Priority: 25,
+		Items:    items,
+	}, nil
+}
+
+func (s *ReleasesSource) toItem(repo string, rel Release) source.Item {
+	timestamp, _ := time.Parse(time.RFC3339, rel.PublishedAt)
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	// Fresh stable releases of things we ship on matter most.
+	priority := source.Medium
+	switch {
+	case rel.Prerelease:
+		priority = source.Low
+	case time.Since(timestamp) < 24*time.Hour:
+		priority = source.High
+	}
+
+	title := repo + " " + rel.TagName
+	if rel.Name != "" && rel.Name != rel.TagName {
+		title += ": " + rel.Name
+	}
+
+	subtitle := "released by " + rel.Author.Login
+	if rel.Prerelease {
+		subtitle = "prerelease by " + rel.Author.Login
+	}
+
+	notes := strings.TrimSpace(rel.Body)
+	if r := []rune(notes); len(r) > maxNotes {
+		notes = string(r[:maxNotes]) + "\n…"
+	}
+
+	return source.Item{
+		ID:        fmt.Sprintf("gh-release-%d", rel.ID),
+		Title:     title,
+		Subtitle:  subtitle,
+		Body:      notes,
+		URL:       rel.HTMLURL,
+		Timestamp: timestamp,
+		Priority:  priority,
+		Category:  "release",
+		Icon:      s.icon,
+		Actions: []source.Action{
+			{Key: "o", Label: "open release", Command: rel.HTMLURL},
+		},
+		Metadata: map[string]any{
+			"repo":       repo,
+			"tag":        rel.TagName,
+			"prerelease": rel.Prerelease,
+			"author":     rel.Author.Login,
+		},
+	}
+}
+
+func fetchReleases(ctx context.Context, repo string, limit int) ([]Release, error) {
+	if strings.Count(repo, "/") != 1 {
+		return nil, fmt.Errorf("want owner/repo, got %q", repo)
+	}
+
+	// Drafts and filtered prereleases take up slots, so ask for extra.
+	reqURL := fmt.Sprintf("%s/repos/%s/releases?per_page=%d", apiURL(), repo, min(limit*3, 100))
+	resp, err := source.DefaultClient.Get(ctx, reqURL, apiHeader())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned %d", resp.StatusCode)
+	}
+
+	var releases []Release
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
@@ -0,0 +1,157 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// releasesServer serves testdata/releases.json as the releases of
+// acme/tool, with an ETag so repeat requests can be answered with a 304.
+// Other repositories are not found.
+func releasesServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/tool/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Accept") != "application/vnd.github+json" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("ETag", `"r1"`)
+		if r.Header.Get("If-None-Match") == `"r1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		http.ServeFile(w, r, "testdata/releases.json")
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("GITHUB_API_URL", srv.URL)
+	t.Setenv("GITHUB_TOKEN", "")
+	return srv
+}
+
+func TestReleasesFetch(t *testing.T) {
+	releasesServer(t)
+	src := NewReleases("Releases", []string{"acme/tool"}, "")
+
+	section, err := src.Fetch(context.Background(), source.Config{Settings: map[string]any{"max": 2, "prereleases": false}})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(section.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(section.Items))
+	}
+
+	got := section.Items[0]
+	if got.ID != "gh-release-2" || got.Title != "acme/tool v2.0.0: Second major" {
+		t.Errorf("item = %q, %q", got.ID, got.Title)
+	}
+	if got.URL != "https://github.com/acme/tool/releases/tag/v2.0.0" || got.Subtitle != "released by hubot" {
+		t.Errorf("item = %q, %q", got.URL, got.Subtitle)
+	}
+	if want := "## Changes\r\n- Faster startup"; got.Body != want {
+		t.Errorf("body = %q, want %q", got.Body, want)
+	}
+	if want := time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC); !got.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", got.Timestamp, want)
+	}
+	if got.Metadata["tag"] != "v2.0.0" || got.Metadata["repo"] != "acme/tool" || got.Metadata["prerelease"] != false {
+		t.Errorf("metadata = %v", got.Metadata)
+	}
+
+	// A name that repeats the tag isn't appended to the title.
+	if got := section.Items[1]; got.ID != "gh-release-1" || got.Title != "acme/tool v1.0.0" {
+		t.Errorf("item = %q, %q", got.ID, got.Title)
+	}
+}
+
+func TestReleasesPrereleases(t *testing.T) {
+	releasesServer(t)
+	src := NewReleases("Releases", []string{"acme/tool"}, "")
+
+	section, err := src.Fetch(context.Background(), source.Config{})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	// The draft is always skipped; prereleases are kept by default.
+	if len(section.Items) != 3 {
+		t.Fatalf("got %d items, want 3", len(section.Items))
+	}
+	got := section.Items[0]
+	if got.ID != "gh-release-3" || got.Priority != source.Low || got.Subtitle != "prerelease by octocat" {
+		t.Errorf("prerelease item = %q, %v, %q", got.ID, got.Priority, got.Subtitle)
+	}
+}
+
+func TestReleasesErrors(t *testing.T) {
+	releasesServer(t)
+
+	// One unreadable repository doesn't fail the others.
+	section, err := NewReleases("Releases", []string{"acme/tool", "acme/missing"}, "").Fetch(context.Background(), source.Config{})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(section.Items) != 3 {
+		t.Errorf("got %d items, want 3", len(section.Items))
+	}
+
+	if _, err := NewReleases("Releases", []string{"acme/missing"}, "").Fetch(context.Background(), source.Config{}); err == nil {
+		t.Error("Fetch with no readable repository: want an error")
+	}
+	if _, err := NewReleases("Releases", []string{"acme"}, "").Fetch(context.Background(), source.Config{}); err == nil {
+		t.Error("Fetch with a malformed repository: want an error")
+	}
+}
+
+// validatorMap is an in-memory source.ValidatorStore.
+type validatorMap struct {
+	mu    sync.Mutex
+	etags map[string]string
+}
+
+func (m *validatorMap) HTTPValidators(url string) (etag, lastModified string, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	etag, ok = m.etags[url]
+	return etag, "", ok
+}
+
+func (m *validatorMap) SaveHTTPValidators(url, etag, lastModified string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.etags[url] = etag
+	return nil
+}
+
+func TestReleasesNotModified(t *testing.T) {
+	releasesServer(t)
+	vs := &validatorMap{etags: map[string]string{}}
+	source.DefaultClient.SetValidatorStore(vs)
+	t.Cleanup(func() { source.DefaultClient.SetValidatorStore(nil) })
+	src := NewReleases("Releases", []string{"acme/tool"}, "")
+
+	ctx, trace := source.WithTrace(context.Background())
+	section, err := src.Fetch(ctx, source.Config{})
+	if err != nil {
+		t.Fatalf("first Fetch: %v", err)
+	}
+	if len(section.Items) != 3 {
+		t.Fatalf("first Fetch: got %d items, want 3", len(section.Items))
+	}
+	trace.Commit(vs)
+
+	section, err = src.Fetch(context.Background(), source.Config{})
+	if err != nil {
+		t.Fatalf("second Fetch: %v", err)
+	}
+	if len(section.Items) != 0 {
+		t.Errorf("second Fetch: got %d items, want none after a 304", len(section.Items))
+	}
+}
@@ -6,106 +6,127 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/pkg/profile"
 	"github.com/jcornudella/hotbrew/pkg/source"
 )
 
 const (
-	baseURL     = "https://hacker-news.firebaseio.com/v0"
-	topStories  = baseURL + "/topstories.json"
-	itemURL     = baseURL + "/item/%d.json"
+	baseURL = "https://hacker-news.firebaseio.com/v0"
+	listURL = baseURL + "/%sstories.json"
+	itemURL = baseURL + "/item/%d.json"
+
+	// maxScan bounds how many story IDs are looked at per fetch when
+	// min_points filters out most of a list.
+	maxScan = 100
 )
 
+// Lists are the story lists the HN API publishes, keyed by setting name.
+var Lists = map[string]bool{
+	"top": true, "new": true, "best": true,
+	"ask": true, "show": true, "job": true,
+}
+
 // Story represents a HN story
 type Story struct {
 	ID          int    `json:"id"`
 	Title       string `json:"title"`
 	URL         string `json:"url"`
+	Text        string `json:"text"`
 	Score       int    `json:"score"`
 	By          string `json:"by"`
 	Time        int64  `json:"time"`
 	Descendants int    `json:"descendants"` // comment count
+	Kids        []int  `json:"kids"`        // top-level comment IDs, in ranked order
 	Type        string `json:"type"`
+	Deleted     bool   `json:"deleted"`
+	Dead        bool   `json:"dead"`
 }
 
-// Source fetches top stories from Hacker News
+// Source fetches stories from Hacker News.
+//
+// Settings:
+//
+//	max        number of stories (default 8)
+//	lists      story lists to merge: top, new, best, ask, show, job (default top)
+//	min_points skip stories below this score; job posts are exempt (default 0)
+//	comments   number of top comments to include in the item body (default 0)
 type Source struct{}
 
 func New() *Source {
 	return &Source{}
 }
 
-func (s *Source) Name() string          { return "Hacker News" }
-func (s *Source) Icon() string          { return "🔶" }
-func (s *Source) TTL() time.Duration    { return 10 * time.Minute }
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "hackernews",
+		Help: "Hacker News front page and story lists",
+		Fields: []source.Field{
+			source.MaxField(8),
+			{Name: "lists", Type: "strings", Setting: true, Help: "story lists to merge: top, new, best, ask, show, job (default top)"},
+			{Name: "min_points", Type: "int", Setting: true, Help: "skip stories below this score (default 0)"},
+			{Name: "comments", Type: "int", Setting: true, Help: "top comments to include in the body (default 0)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			return New(), nil
+		},
+	})
+}
+
+func (s *Source) Name() string       { return "Hacker News" }
+func (s *Source) Icon() string       { return "🔶" }
+func (s *Source) TTL() time.Duration { return 10 * time.Minute }
 
 func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
-	// Get max items from config
-	maxItems := 8
-	if max, ok := cfg.Settings["max"].(int); ok {
-		maxItems = max
+	maxItems := cfg.Int("max", 8)
+	minPoints := cfg.Int("min_points", 0)
+	numComments := cfg.Int("comments", 0)
+
+	lists := cfg.Strings("lists")
+	if len(lists) == 0 {
+		lists = []string{"top"}
+	}
+	for _, l := range lists {
+		if !Lists[l] {
+			return nil, fmt.Errorf("hackernews: unknown list %q (want top, new, best, ask, show or job)", l)
+		}
 	}
 
-	// Fetch top story IDs
-	ids, err := fetchTopStoryIDs(ctx, maxItems)
+	ids, listOf, err := fetchLists(ctx, lists)
 	if err != nil {
 		return nil, err
 	}
+	if len(ids) > maxScan {
+		ids = ids[:maxScan]
+	}
 
-	// Fetch stories in parallel
-	stories := fetchStories(ctx, ids)
-
-	// Convert to source.Items
-	items := make([]source.Item, 0, len(stories))
-	for _, story := range stories {
-		if story == nil {
-			continue
-		}
-
-		timestamp := time.Unix(story.Time, 0)
-
-		// Priority based on score
-		priority := source.Low
-		switch {
-		case story.Score > 500:
-			priority = source.Urgent
-		case story.Score > 200:
-			priority = source.High
-		case story.Score > 50:
-			priority = source.Medium
+	// Fetch stories in batches until enough pass the points threshold.
+	var items []source.Item
+	for start := 0; start < len(ids) && len(items) < maxItems; start += maxItems {
+		end := min(start+maxItems, len(ids))
+		for _, story := range fetchStories(ctx, ids[start:end]) {
+			if story == nil || story.ID == 0 || story.Deleted || story.Dead {
+				continue
+			}
+			if story.Type != "job" && story.Score < minPoints {
+				continue
+			}
+			if len(items) >= maxItems {
+				break
+			}
+			var comments []string
+			if numComments > 0 {
+				comments = fetchComments(ctx, story.Kids, numComments)
+			}
+			items = append(items, toItem(story, listOf[story.ID], comments))
 		}
-
-		// HN discussion URL
-		hnURL := fmt.Sprintf("https://news.ycombinator.com/item?id=%d", story.ID)
-
-		subtitle := fmt.Sprintf("%d points by %s • %d comments", story.Score, story.By, story.Descendants)
-
-		url := story.URL
-		if url == "" {
-			url = hnURL
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-
-		items = append(items, source.Item{
-			ID:        fmt.Sprintf("hn-%d", story.ID),
-			Title:     story.Title,
-			Subtitle:  subtitle,
-			URL:       url,
-			Timestamp: timestamp,
-			Priority:  priority,
-			Category:  "hackernews",
-			Icon:      "🔶",
-			Actions: []source.Action{
-				{Key: "o", Label: "open article", Command: url},
-				{Key: "c", Label: "open comments", Command: hnURL},
-			},
-			Metadata: map[string]any{
-				"score":    story.Score,
-				"comments": story.Descendants,
-				"hn_url":   hnURL,
-			},
-		})
 	}
 
 	return &source.Section{
@@ -116,27 +137,119 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 	}, nil
 }
 
-func fetchTopStoryIDs(ctx context.Context, limit int) ([]int, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", topStories, nil)
+func toItem(story *Story, list string, comments []string) source.Item {
+	timestamp := time.Unix(story.Time, 0)
+
+	// Priority based on score
+	priority := source.Low
+	switch {
+	case story.Score > 500:
+		priority = source.Urgent
+	case story.Score > 200:
+		priority = source.High
+	case story.Score > 50:
+		priority = source.Medium
+	}
+
+	// HN discussion URL
+	hnURL := fmt.Sprintf("https://news.ycombinator.com/item?id=%d", story.ID)
+
+	subtitle := fmt.Sprintf("%d points by %s • %d comments", story.Score, story.By, story.Descendants)
+	if story.Type == "job" {
+		subtitle = "job posting"
+	}
+
+	url := story.URL
+	if url == "" {
+		url = hnURL
+	}
+
+	// Ask/Show HN posts carry their own text; comments follow it.
+	var body []string
+	if text := sanitize.StripHTML(story.Text); text != "" {
+		body = append(body, text)
+	}
+	body = append(body, comments...)
+
+	return source.Item{
+		ID:        fmt.Sprintf("hn-%d", story.ID),
+		Title:     story.Title,
+		Subtitle:  subtitle,
+		Body:      strings.Join(body, "\n\n"),
+		URL:       url,
+		Timestamp: timestamp,
+		Priority:  priority,
+		Category:  "hackernews",
+		Icon:      "🔶",
+		Actions: []source.Action{
+			{Key: "o", Label: "open article", Command: url},
+			{Key: "c", Label: "open comments", Command: hnURL},
+		},
+		Metadata: map[string]any{
+			"points":   story.Score,
+			"comments": story.Descendants,
+			"author":   story.By,
+			"list":     list,
+			"hn_url":   hnURL,
+		},
+	}
+}
+
+// fetchLists fetches the story IDs of each list and merges them,
+// interleaving the lists so each contributes its best stories first.
+// It also reports which list each ID was first seen in.
+func fetchLists(ctx context.Context, lists []string) ([]int, map[int]string, error) {
+	all := make([][]int, len(lists))
+	for i, list := range lists {
+		ids, err := fetchStoryIDs(ctx, list)
+		if err != nil {
+			return nil, nil, err
+		}
+		all[i] = ids
+	}
+
+	var merged []int
+	listOf := make(map[int]string)
+	for pos := 0; ; pos++ {
+		done := true
+		for i, ids := range all {
+			if pos >= len(ids) {
+				continue
+			}
+			done = false
+			if _, seen := listOf[ids[pos]]; seen {
+				continue
+			}
+			listOf[ids[pos]] = lists[i]
+			merged = append(merged, ids[pos])
+		}
+		if done {
+			return merged, listOf, nil
+		}
+	}
+}
+
+// fetchStoryIDs fetches one ranking list. The request is deliberately not
+// conditional: with several lists merged, one 304 must not hide the others.
+func fetchStoryIDs(ctx context.Context, list string) ([]int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(listURL, list), nil)
 	if err != nil {
 		return nil, err
 	}
-
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := source.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("hackernews: %s stories: status %d", list, resp.StatusCode)
+	}
+
 	var ids []int
 	if err := json.NewDecoder(resp.Body).Decode(&ids); err != nil {
 		return nil, err
 	}
-
-	if len(ids) > limit {
-		ids = ids[:limit]
-	}
-
 	return ids, nil
 }
 
@@ -159,6 +272,26 @@ func fetchStories(ctx context.Context, ids []int) []*Story {
 	return stories
 }
 
+// fetchComments returns the text of up to n live top-level comments,
+// each prefixed with its author.
+func fetchComments(ctx context.Context, kids []int, n int) []string {
+	// Ask for a few extra in case some are deleted.
+	if len(kids) > n+3 {
+		kids = kids[:n+3]
+	}
+	var out []string
+	for _, c := range fetchStories(ctx, kids) {
+		if c == nil || c.Deleted || c.Dead || c.Text == "" {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s: %s", c.By, sanitize.StripHTML(c.Text)))
+		if len(out) == n {
+			break
+		}
+	}
+	return out
+}
+
 func fetchStory(ctx context.Context, id int) (*Story, error) {
 	url := fmt.Sprintf(itemURL, id)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -166,7 +299,7 @@ func fetchStory(ctx context.Context, id int) (*Story, error) {
 		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := source.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
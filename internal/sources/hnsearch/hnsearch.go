@@ -4,12 +4,14 @@ package hnsearch
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/jcornudella/hotbrew/pkg/profile"
 	"github.com/jcornudella/hotbrew/pkg/source"
 )
 
@@ -51,6 +53,23 @@ func New(name string, queries []string, icon string) *Source {
 	}
 }
 
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "hnsearch",
+		Help: "Hacker News stories matching search queries",
+		Fields: []source.Field{
+			{Name: "queries", Type: "strings", Required: true, Help: "Algolia search queries"},
+			source.MaxField(5),
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if len(spec.Queries) == 0 {
+				return nil, errors.New("queries is required")
+			}
+			return New(spec.Name, spec.Queries, spec.Icon), nil
+		},
+	})
+}
+
 func (s *Source) Name() string       { return s.name }
 func (s *Source) Icon() string       { return s.icon }
 func (s *Source) TTL() time.Duration { return 15 * time.Minute }
@@ -153,16 +172,15 @@ func search(ctx context.Context, query string, limit int) ([]Hit, error) {
 	params.Set("numericFilters", "points>10")             // Only stories with some traction
 
 	reqURL := searchURL + "?" + params.Encode()
-	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	resp, err := source.DefaultClient.Get(ctx, reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("hnsearch: status %d", resp.StatusCode)
 	}
-	defer resp.Body.Close()
 
 	var result SearchResult
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
@@ -0,0 +1,544 @@
+// Package inbox provides an IMAP source that turns newsletter emails into items.
+package inbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// maxBody caps the text kept in an item body.
+const maxBody = 8000
+
+var (
+	textLink    = regexp.MustCompile(`https?://[^\s<>"')\]]+`)
+	browserLink = regexp.MustCompile(`(?i)(view|read|open)\b.*\b(browser|online|web)|web version`)
+	skipLink    = regexp.MustCompile(`(?i)unsubscribe|preferences|manage.*subscription|opt[-_ ]?out|/track/open`)
+)
+
+// socialHosts are footer links, not stories.
+var socialHosts = map[string]bool{
+	"twitter.com":   true,
+	"x.com":         true,
+	"facebook.com":  true,
+	"instagram.com": true,
+	"linkedin.com":  true,
+	"bsky.app":      true,
+}
+
+// Source reads unseen messages from one IMAP folder.
+//
+// The server is an imaps:// (TLS, the default) or imap:// URL, so the
+// source can also be pointed at a local stand-in. The password is read
+// from the environment variable named by password_env.
+//
+// Settings:
+//
+//	max           number of messages (default 20)
+//	password_env  variable holding the password (default IMAP_PASSWORD)
+//	mark_seen     flag fetched messages as seen once sync has stored them (default false)
+//	split         make one item per linked story in digest-style newsletters (default false)
+type Source struct {
+	name     string
+	icon     string
+	server   *url.URL
+	username string
+	folder   string
+}
+
+// New creates an IMAP source. server is a URL such as
+// "imaps://imap.fastmail.com"; an empty folder means INBOX.
+func New(name, server, username, folder, icon string) (*Source, error) {
+	if icon == "" {
+		icon = "📬"
+	}
+	if folder == "" {
+		folder = "INBOX"
+	}
+	if !strings.Contains(server, "://") {
+		server = "imaps://" + server
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, fmt.Errorf("bad server %q: %w", server, err)
+	}
+	if u.Scheme != "imap" && u.Scheme != "imaps" {
+		return nil, fmt.Errorf("bad server %q: want an imap:// or imaps:// URL", server)
+	}
+	if u.Port() == "" {
+		port := "993"
+		if u.Scheme == "imap" {
+			port = "143"
+		}
+		u.Host += ":" + port
+	}
+	return &Source{
+		name:     name,
+		icon:     icon,
+		server:   u,
+		username: username,
+		folder:   folder,
+	}, nil
+}
+
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "imap",
+		Help: "newsletters from an IMAP mailbox folder",
+		Fields: []source.Field{
+			{Name: "server", Type: "string", Required: true, Help: "imaps://host[:port] or imap://host[:port]"},
+			{Name: "username", Type: "string", Required: true, Help: "login name"},
+			{Name: "folder", Type: "string", Help: "mailbox folder (default INBOX)"},
+			source.MaxField(20),
+			{Name: "password_env", Type: "string", Setting: true, Help: "variable holding the password (default IMAP_PASSWORD)"},
+			{Name: "mark_seen", Type: "bool", Setting: true, Help: "flag messages as seen once stored (default false)"},
+			{Name: "split", Type: "bool", Setting: true, Help: "one item per linked story (default false)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if spec.Server == "" || spec.Username == "" {
+				return nil, errors.New("server and username are required")
+			}
+			return New(spec.Name, spec.Server, spec.Username, spec.Folder, spec.Icon)
+		},
+	})
+}
+
+func (s *Source) Name() string       { return s.name }
+func (s *Source) Icon() string       { return s.icon }
+func (s *Source) TTL() time.Duration { return 15 * time.Minute }
+
+func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	maxItems := cfg.Int("max", 20)
+	markSeen := cfg.Bool("mark_seen", false)
+	split := cfg.Bool("split", false)
+	passwordEnv := cfg.String("password_env", "IMAP_PASSWORD")
+	password := os.Getenv(passwordEnv)
+	if password == "" {
+		return nil, fmt.Errorf("imap: %s is not set", passwordEnv)
+	}
+
+	raws, uids, err := s.fetchUnseen(ctx, password, maxItems)
+	if err != nil {
+		return nil, err
+	}
+	// Only flag the messages once their items are safely stored; callers
+	// that don't store, like `sources check`, leave them unseen.
+	if markSeen && len(uids) > 0 {
+		source.AfterStore(ctx, func(ctx context.Context) error {
+			return s.markSeen(ctx, password, uids)
+		})
+	}
+
+	var items []source.Item
+	for _, raw := range raws {
+		msg, err := parseMessage(raw)
+		if err != nil {
+			continue // skip messages we can't parse, don't fail the folder
+		}
+		if split {
+			if stories := msg.stories(s.icon); len(stories) > 0 {
+				items = append(items, stories...)
+				continue
+			}
+		}
+		items = append(items, msg.item(s.icon))
+	}
+
+	return &source.Section{
+		Name:     s.name,
+		Icon:     s.icon,
+		Priority: 50,
+		Items:    items,
+	}, nil
+}
+
+// connect logs in and selects the folder. The connection is dropped when
+// ctx is done; the returned function logs out.
+func (s *Source) connect(ctx context.Context, password string, readOnly bool) (*client.Client, func(), error) {
+	var c *client.Client
+	var err error
+	if s.server.Scheme == "imap" {
+		c, err = client.Dial(s.server.Host)
+	} else {
+		c, err = client.DialTLS(s.server.Host, nil)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("imap: connect: %w", err)
+	}
+
+	// go-imap has no context support; drop the connection on cancel.
+	stop := context.AfterFunc(ctx, func() { c.Terminate() })
+	closeFn := func() {
+		stop()
+		c.Logout()
+	}
+
+	if err := c.Login(s.username, password); err != nil {
+		closeFn()
+		return nil, nil, fmt.Errorf("imap: login: %w", err)
+	}
+	if _, err := c.Select(s.folder, readOnly); err != nil {
+		closeFn()
+		return nil, nil, fmt.Errorf("imap: select %s: %w", s.folder, err)
+	}
+	return c, closeFn, nil
+}
+
+// fetchUnseen returns the raw RFC 822 text of up to limit unseen messages,
+// newest first, and their UIDs. The folder is opened read-only, so the
+// messages stay unseen.
+func (s *Source) fetchUnseen(ctx context.Context, password string, limit int) ([][]byte, []uint32, error) {
+	c, closeFn, err := s.connect(ctx, password, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeFn()
+
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag}
+	uids, err := c.UidSearch(criteria)
+	if err != nil {
+		return nil, nil, fmt.Errorf("imap: search: %w", err)
+	}
+	if len(uids) == 0 {
+		return nil, nil, nil
+	}
+	// UIDs ascend with arrival, so the newest are last.
+	if len(uids) > limit {
+		uids = uids[len(uids)-limit:]
+	}
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uids...)
+	section := &imap.BodySectionName{Peek: true}
+	messages := make(chan *imap.Message, len(uids))
+	if err := c.UidFetch(seqset, []imap.FetchItem{section.FetchItem(), imap.FetchUid}, messages); err != nil {
+		return nil, nil, fmt.Errorf("imap: fetch: %w", err)
+	}
+
+	var raws [][]byte
+	for msg := range messages {
+		body := msg.GetBody(section)
+		if body == nil {
+			continue
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			continue
+		}
+		raws = append([][]byte{data}, raws...)
+	}
+	return raws, uids, nil
+}
+
+// markSeen flags the messages with the given UIDs as seen.
+func (s *Source) markSeen(ctx context.Context, password string, uids []uint32) error {
+	c, closeFn, err := s.connect(ctx, password, false)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uids...)
+	flags := []interface{}{imap.SeenFlag}
+	if err := c.UidStore(seqset, imap.FormatFlagsOp(imap.AddFlags, true), flags, nil); err != nil {
+		return fmt.Errorf("imap: mark seen: %w", err)
+	}
+	return nil
+}
+
+// message is the part of an email an item is built from.
+type message struct {
+	id          string
+	subject     string
+	from        string
+	domain      string // of the sender's address
+	date        time.Time
+	text        string
+	html        string
+	unsubscribe map[string]bool
+}
+
+func parseMessage(raw []byte) (*message, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	dec := new(mime.WordDecoder)
+	subject, err := dec.DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		subject = m.Header.Get("Subject")
+	}
+	from, domain := m.Header.Get("From"), ""
+	if addr, err := mail.ParseAddress(from); err == nil {
+		from = addr.Name
+		if from == "" {
+			from = addr.Address
+		}
+		if i := strings.LastIndex(addr.Address, "@"); i >= 0 {
+			domain = strings.ToLower(addr.Address[i+1:])
+		}
+	}
+	date, _ := m.Header.Date()
+
+	msg := &message{
+		id:          strings.Trim(m.Header.Get("Message-Id"), "<> "),
+		subject:     sanitize.Text(strings.TrimSpace(subject)),
+		from:        sanitize.Text(from),
+		domain:      domain,
+		date:        date,
+		unsubscribe: map[string]bool{},
+	}
+	for _, u := range textLink.FindAllString(m.Header.Get("List-Unsubscribe"), -1) {
+		msg.unsubscribe[u] = true
+	}
+	if err := msg.readPart(m.Header, m.Body); err != nil {
+		return nil, err
+	}
+	if msg.id == "" {
+		msg.id = fmt.Sprintf("%s-%d", msg.subject, msg.date.Unix())
+	}
+	return msg, nil
+}
+
+// header is satisfied by both mail.Header and textproto.MIMEHeader.
+type header interface {
+	Get(key string) string
+}
+
+// readPart walks a MIME part, keeping the first text/plain and text/html bodies.
+func (msg *message) readPart(h header, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			p, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := msg.readPart(p.Header, p); err != nil {
+				return err
+			}
+		}
+	}
+
+	var r io.Reader = body
+	switch strings.ToLower(h.Get("Content-Transfer-Encoding")) {
+	case "quoted-printable":
+		r = quotedprintable.NewReader(body)
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, &newlineStripper{r: body})
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	switch mediaType {
+	case "text/plain":
+		if msg.text == "" {
+			msg.text = string(data)
+		}
+	case "text/html":
+		if msg.html == "" {
+			msg.html = string(data)
+		}
+	}
+	return nil
+}
+
+// link is an anchor found in a message.
+type link struct {
+	url  string
+	text string
+}
+
+// links returns the message's links, minus unsubscribe, tracking and
+// social ones, in document order.
+func (msg *message) links() []link {
+	var out []link
+	seen := map[string]bool{}
+	add := func(u, text string) {
+		u = strings.TrimSpace(u)
+		if !strings.HasPrefix(u, "http") || seen[u] || msg.unsubscribe[u] {
+			return
+		}
+		if skipLink.MatchString(u) || skipLink.MatchString(text) {
+			return
+		}
+		if parsed, err := url.Parse(u); err != nil || socialHosts[strings.TrimPrefix(parsed.Hostname(), "www.")] {
+			return
+		}
+		seen[u] = true
+		out = append(out, link{url: u, text: strings.Join(strings.Fields(text), " ")})
+	}
+
+	if msg.html != "" {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(msg.html))
+		if err == nil {
+			doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+				href, _ := a.Attr("href")
+				add(href, a.Text())
+			})
+			return out
+		}
+	}
+	for _, u := range textLink.FindAllString(msg.text, -1) {
+		add(u, "")
+	}
+	return out
+}
+
+// url picks the link that best stands for the whole message: a "view in
+// browser" link if there is one, otherwise the first link that isn't the
+// sender's homepage. Every issue shares that masthead link, so using it
+// would give them all one fingerprint.
+func (msg *message) url(links []link) string {
+	for _, l := range links {
+		if browserLink.MatchString(l.text) {
+			return l.url
+		}
+	}
+	for _, l := range links {
+		if !msg.homepage(l.url) {
+			return l.url
+		}
+	}
+	return ""
+}
+
+// homepage reports whether u is the root of the sender's domain or of a
+// domain it belongs to.
+func (msg *message) homepage(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil || msg.domain == "" || strings.Trim(parsed.Path, "/") != "" {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	return host == msg.domain || strings.HasSuffix(msg.domain, "."+host) || strings.HasSuffix(host, "."+msg.domain)
+}
+
+func (msg *message) body() string {
+	text := strings.TrimSpace(msg.text)
+	if text == "" {
+		text = sanitize.StripHTML(msg.html)
+	}
+	text = sanitize.Text(text)
+	if r := []rune(text); len(r) > maxBody {
+		text = string(r[:maxBody]) + "\n…"
+	}
+	return text
+}
+
+func (msg *message) timestamp() time.Time {
+	if msg.date.IsZero() {
+		return time.Now()
+	}
+	return msg.date
+}
+
+func (msg *message) item(icon string) source.Item {
+	itemURL := msg.url(msg.links())
+
+	var actions []source.Action
+	if itemURL != "" {
+		actions = []source.Action{{Key: "o", Label: "open", Command: itemURL}}
+	}
+
+	return source.Item{
+		ID:        "mail-" + msg.id,
+		Title:     msg.subject,
+		Subtitle:  msg.from,
+		Body:      msg.body(),
+		URL:       itemURL,
+		Timestamp: msg.timestamp(),
+		Priority:  source.Low,
+		Category:  "newsletter",
+		Icon:      icon,
+		Actions:   actions,
+		Metadata: map[string]any{
+			"from":    msg.from,
+			"subject": msg.subject,
+		},
+	}
+}
+
+// stories splits a digest-style newsletter into one item per story link.
+// Links whose text is too short to be a headline ("Read more", logos) are
+// skipped.
+func (msg *message) stories(icon string) []source.Item {
+	var items []source.Item
+	for _, l := range msg.links() {
+		if len(strings.Fields(l.text)) < 4 || browserLink.MatchString(l.text) {
+			continue
+		}
+		items = append(items, source.Item{
+			ID:        fmt.Sprintf("mail-%s#%d", msg.id, len(items)+1),
+			Title:     sanitize.Text(l.text),
+			Subtitle:  fmt.Sprintf("%s • %s", msg.from, msg.subject),
+			URL:       l.url,
+			Timestamp: msg.timestamp(),
+			Priority:  source.Low,
+			Category:  "newsletter",
+			Icon:      icon,
+			Actions: []source.Action{
+				{Key: "o", Label: "open", Command: l.url},
+			},
+			Metadata: map[string]any{
+				"from":    msg.from,
+				"subject": msg.subject,
+			},
+		})
+	}
+	return items
+}
+
+// newlineStripper drops line breaks, which base64 bodies wrap at 76 columns.
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n *newlineStripper) Read(p []byte) (int, error) {
+	for {
+		c, err := n.r.Read(p)
+		kept := 0
+		for _, b := range p[:c] {
+			if b != '\r' && b != '\n' {
+				p[kept] = b
+				kept++
+			}
+		}
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
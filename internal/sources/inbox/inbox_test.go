@@ -0,0 +1,134 @@
+package inbox
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/server"
+
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+const newsletter = "From: Weekly <news@example.org>\r\n" +
+	"To: me@example.org\r\n" +
+	"Subject: This week in Go\r\n" +
+	"Date: Mon, 05 Jan 2026 09:00:00 +0000\r\n" +
+	"Message-ID: <weekly-1@example.org>\r\n" +
+	"Content-Type: text/html\r\n" +
+	"\r\n" +
+	"<p>Top story: <a href=\"https://example.org/story\">Generics, two years on</a></p>"
+
+// startServer serves the go-imap memory backend, whose one user is
+// "username"/"password", on a loopback port with newsletter added unseen.
+func startServer(t *testing.T) string {
+	t.Helper()
+	be := memory.New()
+	user, err := be.Login(nil, "username", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mbox, err := user.GetMailbox("INBOX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mbox.(*memory.Mailbox).CreateMessage(nil, time.Now(), bytes.NewBufferString(newsletter)); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := server.New(be)
+	srv.AllowInsecureAuth = true
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+	return "imap://" + l.Addr().String()
+}
+
+func TestFetchMarkSeen(t *testing.T) {
+	t.Setenv("IMAP_PASSWORD", "password")
+	src, err := New("Mail", startServer(t), "username", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := source.Config{Settings: map[string]any{"mark_seen": true}}
+
+	fetch := func(ctx context.Context) []source.Item {
+		t.Helper()
+		section, err := src.Fetch(ctx, cfg)
+		if err != nil {
+			t.Fatalf("Fetch: %v", err)
+		}
+		return section.Items
+	}
+
+	// The memory backend's own message is already seen, so only the
+	// newsletter comes back.
+	items := fetch(context.Background())
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	if got := items[0]; got.Title != "This week in Go" || got.URL != "https://example.org/story" || got.Subtitle != "Weekly" {
+		t.Errorf("item = %q, %q, %q", got.Title, got.URL, got.Subtitle)
+	}
+
+	// Without a Trace nothing is stored, so the message stays unseen.
+	if items := fetch(context.Background()); len(items) != 1 {
+		t.Fatalf("after an unstored fetch: got %d items, want 1", len(items))
+	}
+
+	// A storing caller marks it seen, but only once it runs the
+	// after-store step.
+	ctx, trace := source.WithTrace(context.Background())
+	fetch(ctx)
+	if items := fetch(context.Background()); len(items) != 1 {
+		t.Fatalf("before the after-store step: got %d items, want 1", len(items))
+	}
+	if err := trace.RunAfterStore(context.Background()); err != nil {
+		t.Fatalf("RunAfterStore: %v", err)
+	}
+	if items := fetch(context.Background()); len(items) != 0 {
+		t.Fatalf("after the after-store step: got %d items, want 0", len(items))
+	}
+}
+
+func TestItemURLSkipsMasthead(t *testing.T) {
+	issue := func(n, story string) string {
+		return "From: Weekly <news@mail.example.org>\r\n" +
+			"Subject: Issue " + n + "\r\n" +
+			"Message-ID: <weekly-" + n + "@example.org>\r\n" +
+			"Content-Type: text/html\r\n" +
+			"\r\n" +
+			"<a href=\"https://www.example.org/\">Weekly</a>" +
+			"<p><a href=\"" + story + "\">" + story + "</a></p>"
+	}
+
+	seen := map[string]bool{}
+	for _, raw := range []string{
+		issue("1", "https://example.org/generics"),
+		issue("2", "https://example.org/iterators"),
+	} {
+		msg, err := parseMessage([]byte(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := msg.item("").URL
+		if got == "https://www.example.org/" || seen[got] {
+			t.Errorf("%s: URL = %q, want its own story link", msg.subject, got)
+		}
+		seen[got] = true
+	}
+
+	msg, err := parseMessage([]byte(issue("3", "https://www.example.org")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msg.item("").URL; got != "" {
+		t.Errorf("masthead-only issue URL = %q, want none", got)
+	}
+}
@@ -0,0 +1,284 @@
+// Package jsonfeed provides a JSON Feed (https://jsonfeed.org) source for digest
+package jsonfeed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// versionPrefix starts the "version" value of every JSON Feed document.
+const versionPrefix = "https://jsonfeed.org/version/"
+
+// Feed is a JSON Feed document (versions 1.0 and 1.1).
+type Feed struct {
+	Version     string   `json:"version"`
+	Title       string   `json:"title"`
+	HomePageURL string   `json:"home_page_url"`
+	FeedURL     string   `json:"feed_url"`
+	Description string   `json:"description"`
+	Icon        string   `json:"icon"`
+	Authors     []Author `json:"authors"`
+	Author      *Author  `json:"author"` // deprecated in 1.1
+	Items       []Item   `json:"items"`
+}
+
+// Item is a single entry in a JSON Feed.
+type Item struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url"`
+	ExternalURL   string       `json:"external_url"`
+	Title         string       `json:"title"`
+	ContentHTML   string       `json:"content_html"`
+	ContentText   string       `json:"content_text"`
+	Summary       string       `json:"summary"`
+	Image         string       `json:"image"`
+	DatePublished string       `json:"date_published"`
+	DateModified  string       `json:"date_modified"`
+	Authors       []Author     `json:"authors"`
+	Author        *Author      `json:"author"` // deprecated in 1.1
+	Tags          []string     `json:"tags"`
+	Attachments   []Attachment `json:"attachments"`
+}
+
+// Author identifies the author of a feed or item.
+type Author struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Avatar string `json:"avatar"`
+}
+
+// Attachment is a related resource such as a podcast episode.
+type Attachment struct {
+	URL               string  `json:"url"`
+	MIMEType          string  `json:"mime_type"`
+	Title             string  `json:"title"`
+	SizeInBytes       int64   `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+// Sniff reports whether data, possibly just the start of a document, looks
+// like a JSON Feed.
+func Sniff(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return false
+	}
+	// Some encoders escape forward slashes.
+	return bytes.Contains(data, []byte("jsonfeed.org/version/")) ||
+		bytes.Contains(data, []byte(`jsonfeed.org\/version\/`))
+}
+
+// Source fetches items from a JSON Feed
+type Source struct {
+	name string
+	url  string
+	icon string
+}
+
+// New creates a new JSON Feed source
+func New(name, url, icon string) *Source {
+	if icon == "" {
+		icon = "📰"
+	}
+	return &Source{
+		name: name,
+		url:  url,
+		icon: icon,
+	}
+}
+
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "jsonfeed",
+		Help: "a JSON Feed",
+		Fields: []source.Field{
+			{Name: "feed_url", Type: "string", Required: true, Help: "feed URL"},
+			source.MaxField(5),
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if spec.FeedURL == "" {
+				return nil, errors.New("feed_url is required")
+			}
+			return New(spec.Name, spec.FeedURL, spec.Icon), nil
+		},
+	})
+}
+
+func (s *Source) Name() string       { return s.name }
+func (s *Source) Icon() string       { return s.icon }
+func (s *Source) TTL() time.Duration { return 15 * time.Minute }
+
+func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/feed+json, application/json")
+	resp, err := source.DefaultClient.Get(ctx, s.url, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jsonfeed: status %d", resp.StatusCode)
+	}
+
+	var feed Feed
+	if err := json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("jsonfeed: decode: %w", err)
+	}
+	if !strings.HasPrefix(feed.Version, versionPrefix) {
+		return nil, fmt.Errorf("jsonfeed: unsupported version %q", feed.Version)
+	}
+
+	maxItems := cfg.Int("max", 5)
+
+	items := make([]source.Item, 0, maxItems)
+	for _, entry := range feed.Items {
+		if len(items) >= maxItems {
+			break
+		}
+		items = append(items, s.convert(entry, feed))
+	}
+
+	return &source.Section{
+		Name:     s.name,
+		Icon:     s.icon,
+		Priority: 50,
+		Items:    items,
+	}, nil
+}
+
+// convert maps a JSON Feed item to a source.Item.
+func (s *Source) convert(entry Item, feed Feed) source.Item {
+	link := entry.URL
+	if link == "" {
+		link = entry.ExternalURL
+	}
+
+	title := entry.Title
+	if title == "" {
+		// Title-less microblog posts: use the start of the text.
+		title = truncate(firstNonEmpty(entry.Summary, entry.ContentText), 80)
+	}
+
+	timestamp := time.Now()
+	if t, ok := parseDate(entry.DatePublished); ok {
+		timestamp = t
+	} else if t, ok := parseDate(entry.DateModified); ok {
+		timestamp = t
+	}
+
+	// Determine priority based on recency
+	priority := source.Low
+	age := time.Since(timestamp)
+	switch {
+	case age < 1*time.Hour:
+		priority = source.High
+	case age < 6*time.Hour:
+		priority = source.Medium
+	}
+
+	meta := map[string]any{}
+	if len(entry.Tags) > 0 {
+		meta["tags"] = entry.Tags
+	}
+	if authors := authorNames(entry.Authors, entry.Author); authors != "" {
+		meta["author"] = authors
+	} else if authors := authorNames(feed.Authors, feed.Author); authors != "" {
+		meta["author"] = authors
+	}
+	if entry.Image != "" {
+		meta["image"] = entry.Image
+	}
+	if len(entry.Attachments) > 0 {
+		attachments := make([]map[string]any, 0, len(entry.Attachments))
+		for _, a := range entry.Attachments {
+			att := map[string]any{"url": a.URL, "mime_type": a.MIMEType}
+			if a.Title != "" {
+				att["title"] = a.Title
+			}
+			if a.SizeInBytes > 0 {
+				att["size_in_bytes"] = a.SizeInBytes
+			}
+			if a.DurationInSeconds > 0 {
+				att["duration_in_seconds"] = a.DurationInSeconds
+			}
+			attachments = append(attachments, att)
+		}
+		meta["attachments"] = attachments
+	}
+
+	body := sanitize.StripHTML(entry.ContentHTML)
+	if body == "" {
+		body = sanitize.Text(entry.ContentText)
+	}
+
+	item := source.Item{
+		ID:        entry.ID,
+		Title:     sanitize.Text(title),
+		Subtitle:  sanitize.Text(entry.Summary),
+		Body:      body,
+		URL:       link,
+		Timestamp: timestamp,
+		Priority:  priority,
+		Category:  "news",
+		Icon:      s.icon,
+		Metadata:  meta,
+	}
+	if link != "" {
+		item.Actions = []source.Action{
+			{Key: "o", Label: "open", Command: link},
+		}
+	}
+	return item
+}
+
+// parseDate parses an RFC 3339 date as required by the spec.
+func parseDate(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, err == nil
+}
+
+// authorNames joins author names, falling back to the 1.0 author field.
+func authorNames(authors []Author, legacy *Author) string {
+	if len(authors) == 0 && legacy != nil {
+		authors = []Author{*legacy}
+	}
+	var names []string
+	for _, a := range authors {
+		if a.Name != "" {
+			names = append(names, a.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func truncate(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,67 @@
+package jsonfeed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer srv.Close()
+
+	section, err := New("Example", srv.URL+"/feed.json", "").Fetch(context.Background(), source.Config{Settings: map[string]any{"max": 2}})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(section.Items) != 2 {
+		t.Fatalf("got %d items, want 2 (max)", len(section.Items))
+	}
+
+	first := section.Items[0]
+	if want := "Hello world & friends.\n\nSecond\nline"; first.Body != want {
+		t.Errorf("content_html body = %q, want %q", first.Body, want)
+	}
+	if first.Title != "First post" || first.Subtitle != "A summary" || first.URL != "https://example.org/first" {
+		t.Errorf("first item = %q, %q, %q", first.Title, first.Subtitle, first.URL)
+	}
+	if want := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC); !first.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", first.Timestamp, want)
+	}
+	if got := first.Metadata["author"]; got != "Feed Author" {
+		t.Errorf("author = %v, want the feed's author", got)
+	}
+
+	second := section.Items[1]
+	if want := "A title-less note[31m in plain text"; second.Body != want {
+		t.Errorf("content_text body = %q, want %q", second.Body, want)
+	}
+	if second.Title != second.Body || second.URL != "https://example.com/linked" {
+		t.Errorf("second item = %q, %q", second.Title, second.URL)
+	}
+	if got := second.Metadata["author"]; got != "Item Author" {
+		t.Errorf("author = %v, want the item's author", got)
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/rss":
+			w.Write([]byte(`{"version": "2.0", "items": []}`))
+		}
+	}))
+	defer srv.Close()
+
+	for _, path := range []string{"/missing", "/rss"} {
+		if _, err := New("Example", srv.URL+path, "").Fetch(context.Background(), source.Config{}); err == nil {
+			t.Errorf("Fetch %s: want an error", path)
+		}
+	}
+}
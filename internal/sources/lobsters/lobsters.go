@@ -4,10 +4,15 @@ package lobsters
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/jcornudella/hotbrew/pkg/profile"
 	"github.com/jcornudella/hotbrew/pkg/source"
 )
 
@@ -24,64 +29,107 @@ type story struct {
 	CreatedAt    string   `json:"created_at"`
 	ShortIDURL   string   `json:"short_id_url"`
 	CommentsURL  string   `json:"comments_url"`
+	UserIsAuthor bool     `json:"user_is_author"`
 }
 
-// Source fetches stories from Lobste.rs.
+// Modes are the front-page listings a source can read.
+var Modes = []string{"hottest", "newest", "active"}
+
+// Source fetches stories from Lobste.rs: one of the front-page listings,
+// or, when tags are given, each tag's own listing merged into one.
 type Source struct {
-	name     string
-	icon     string
-	tags     []string // optional tag filter
-	endpoint string
+	name    string
+	icon    string
+	tags    []string // read /t/<tag>.json for each instead of the front page
+	mode    string   // default for the mode setting, one of Modes
+	baseURL string
 }
 
-// New creates a Lobste.rs source.
-// If tags are provided, only stories matching those tags are included.
-func New(name string, tags []string, icon string) *Source {
+// New creates a Lobste.rs source. An empty mode means "hottest". Tag
+// listings are ordered to match mode: by score for hottest, newest first
+// for newest, by comment count for active.
+func New(name string, tags []string, mode, icon string) *Source {
 	if icon == "" {
 		icon = "🦞"
 	}
+	if mode == "" {
+		mode = "hottest"
+	}
 	return &Source{
-		name:     name,
-		icon:     icon,
-		tags:     tags,
-		endpoint: "https://lobste.rs/hottest.json",
+		name:    name,
+		icon:    icon,
+		tags:    tags,
+		mode:    mode,
+		baseURL: "https://lobste.rs",
 	}
 }
 
-func (s *Source) Name() string        { return s.name }
-func (s *Source) Icon() string        { return s.icon }
-func (s *Source) TTL() time.Duration  { return 15 * time.Minute }
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "lobsters",
+		Help: "Lobste.rs stories, from the front page or per tag",
+		Fields: []source.Field{
+			{Name: "tags", Type: "strings", Help: "read these tags' listings instead of the front page"},
+			source.MaxField(10),
+			{Name: "mode", Type: "string", Setting: true, Help: "hottest, newest or active (default hottest)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			mode := source.Config{Settings: spec.Settings}.String("mode", "hottest")
+			if !slices.Contains(Modes, mode) {
+				return nil, fmt.Errorf("unknown mode %q (want one of %s)", mode, strings.Join(Modes, ", "))
+			}
+			return New(spec.Name, spec.Tags, mode, spec.Icon), nil
+		},
+	})
+}
 
-func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
-	maxItems := 10
-	if max, ok := cfg.Settings["max"].(int); ok {
-		maxItems = max
-	}
+func (s *Source) Name() string       { return s.name }
+func (s *Source) Icon() string       { return s.icon }
+func (s *Source) TTL() time.Duration { return 15 * time.Minute }
 
-	req, err := http.NewRequestWithContext(ctx, "GET", s.endpoint, nil)
-	if err != nil {
-		return nil, err
+func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	maxItems := cfg.Int("max", 10)
+	mode := cfg.String("mode", s.mode)
+	if !slices.Contains(Modes, mode) {
+		return nil, fmt.Errorf("lobsters: unknown mode %q (want one of %s)", mode, strings.Join(Modes, ", "))
 	}
-	req.Header.Set("User-Agent", "hotbrew/1.0")
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
+	listings := []string{"/" + mode + ".json"}
+	if len(s.tags) > 0 {
+		listings = listings[:0]
+		for _, tag := range s.tags {
+			listings = append(listings, "/t/"+url.PathEscape(tag)+".json")
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("lobsters: status %d", resp.StatusCode)
+	var stories []story
+	var errs []error
+	seen := map[string]bool{}
+	for _, path := range listings {
+		page, err := s.fetchListing(ctx, path)
+		switch {
+		case errors.Is(err, source.ErrNotModified):
+			continue
+		case err != nil:
+			errs = append(errs, err)
+			continue
+		}
+		for _, st := range page {
+			if !seen[st.ShortID] {
+				seen[st.ShortID] = true
+				stories = append(stories, st)
+			}
+		}
 	}
 
-	var stories []story
-	if err := json.NewDecoder(resp.Body).Decode(&stories); err != nil {
-		return nil, err
+	// Only fail the sync when no listing could be read.
+	if len(errs) > 0 && len(stories) == 0 {
+		return nil, errors.Join(errs...)
 	}
 
-	tagSet := map[string]bool{}
-	for _, t := range s.tags {
-		tagSet[t] = true
+	// Tag listings come in the site's own order whatever the mode.
+	if len(s.tags) > 0 {
+		order(stories, mode)
 	}
 
 	var items []source.Item
@@ -90,17 +138,12 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 			break
 		}
 
-		// Tag filter: if tags specified, story must match at least one.
-		if len(tagSet) > 0 && !matchesAnyTag(st.Tags, tagSet) {
-			continue
-		}
-
 		// Skip heavily flagged stories.
 		if st.Flags > 2 {
 			continue
 		}
 
-		timestamp, _ := time.Parse(time.RFC3339, st.CreatedAt)
+		timestamp := st.created()
 		if timestamp.IsZero() {
 			timestamp = time.Now()
 		}
@@ -115,27 +158,34 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 			priority = source.Medium
 		}
 
+		metadata := map[string]any{
+			"points":         st.Score,
+			"comments":       st.CommentCount,
+			"tags":           st.Tags,
+			"submitter":      st.Submitter,
+			"user_is_author": st.UserIsAuthor,
+			"comments_url":   st.CommentsURL,
+			"lobsters_url":   st.ShortIDURL,
+		}
+		if st.UserIsAuthor {
+			// The submitter wrote the linked piece.
+			metadata["author"] = st.Submitter
+		}
+
 		items = append(items, source.Item{
-			ID:       st.ShortID,
-			Title:    st.Title,
-			Subtitle: st.Description,
-			URL:      st.URL,
-			Priority: priority,
+			ID:        st.ShortID,
+			Title:     st.Title,
+			Subtitle:  st.Description,
+			URL:       st.URL,
+			Priority:  priority,
 			Timestamp: timestamp,
-			Category: "tech",
-			Icon:     s.icon,
+			Category:  "tech",
+			Icon:      s.icon,
 			Actions: []source.Action{
 				{Key: "o", Label: "open", Command: st.URL},
 				{Key: "c", Label: "comments", Command: st.CommentsURL},
 			},
-			Metadata: map[string]any{
-				"points":       st.Score,
-				"comments":     st.CommentCount,
-				"tags":         st.Tags,
-				"submitter":    st.Submitter,
-				"comments_url": st.CommentsURL,
-				"lobsters_url": st.ShortIDURL,
-			},
+			Metadata: metadata,
 		})
 	}
 
@@ -147,11 +197,40 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 	}, nil
 }
 
-func matchesAnyTag(storyTags []string, filter map[string]bool) bool {
-	for _, t := range storyTags {
-		if filter[t] {
-			return true
-		}
+func (s *Source) fetchListing(ctx context.Context, path string) ([]story, error) {
+	resp, err := source.DefaultClient.Get(ctx, s.baseURL+path, nil)
+	if err != nil {
+		return nil, err
 	}
-	return false
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("lobsters %s: status %d", path, resp.StatusCode)
+	}
+
+	var stories []story
+	if err := json.NewDecoder(resp.Body).Decode(&stories); err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
+
+// order sorts stories read from tag listings the way mode orders the
+// front page.
+func order(stories []story, mode string) {
+	sort.SliceStable(stories, func(i, j int) bool {
+		switch mode {
+		case "newest":
+			return stories[i].created().After(stories[j].created())
+		case "active":
+			return stories[i].CommentCount > stories[j].CommentCount
+		default:
+			return stories[i].Score > stories[j].Score
+		}
+	})
+}
+
+func (st story) created() time.Time {
+	t, _ := time.Parse(time.RFC3339, st.CreatedAt)
+	return t
 }
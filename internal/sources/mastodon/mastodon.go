@@ -0,0 +1,343 @@
+// Package mastodon provides a Mastodon timeline source using the public REST API.
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// DefaultInstance is used when a profile entry doesn't name one.
+const DefaultInstance = "mastodon.social"
+
+// Status represents a Mastodon status (a post)
+type Status struct {
+	ID              string  `json:"id"`
+	URL             string  `json:"url"`
+	URI             string  `json:"uri"`
+	Content         string  `json:"content"`
+	SpoilerText     string  `json:"spoiler_text"`
+	CreatedAt       string  `json:"created_at"`
+	Language        string  `json:"language"`
+	ReblogsCount    int     `json:"reblogs_count"`
+	FavouritesCount int     `json:"favourites_count"`
+	RepliesCount    int     `json:"replies_count"`
+	Sensitive       bool    `json:"sensitive"`
+	Account         Account `json:"account"`
+	Reblog          *Status `json:"reblog"`
+	Card            *Card   `json:"card"`
+	Tags            []Tag   `json:"tags"`
+	InReplyToID     *string `json:"in_reply_to_id"`
+}
+
+// Account represents the author of a status
+type Account struct {
+	ID          string `json:"id"`
+	Acct        string `json:"acct"`
+	DisplayName string `json:"display_name"`
+	URL         string `json:"url"`
+}
+
+// Card is the link preview attached to a status
+type Card struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+}
+
+// Tag is a hashtag used in a status
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Source reads hashtag timelines, lists and account statuses from one
+// Mastodon instance. Lists are private, so reading them needs
+// MASTODON_TOKEN; hashtags and public accounts don't.
+//
+// Settings:
+//
+//	max         number of statuses (default 10)
+//	links_only  skip statuses without a link card (default false)
+//	min_boosts  skip statuses boosted fewer times than this (default 0)
+type Source struct {
+	name     string
+	icon     string
+	instance string
+	hashtags []string
+	accounts []string // "user" or "user@other.instance"
+	lists    []string // list IDs
+}
+
+// New creates a Mastodon source reading from instance, a host name such as
+// "hachyderm.io". An empty instance means DefaultInstance.
+func New(name, instance string, hashtags, accounts, lists []string, icon string) *Source {
+	if icon == "" {
+		icon = "🐘"
+	}
+	if instance == "" {
+		instance = DefaultInstance
+	}
+	instance = strings.TrimPrefix(strings.TrimPrefix(instance, "https://"), "http://")
+	return &Source{
+		name:     name,
+		icon:     icon,
+		instance: strings.TrimRight(instance, "/"),
+		hashtags: hashtags,
+		accounts: accounts,
+		lists:    lists,
+	}
+}
+
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "mastodon",
+		Help: "Mastodon hashtags, lists and accounts",
+		Fields: []source.Field{
+			{Name: "instance", Type: "string", Help: "instance host (default " + DefaultInstance + ")"},
+			{Name: "tags", Type: "strings", Help: "hashtags to follow"},
+			{Name: "accounts", Type: "strings", Help: "accounts whose statuses to read"},
+			{Name: "lists", Type: "strings", Help: "list IDs (needs MASTODON_TOKEN)"},
+			source.MaxField(10),
+			{Name: "links_only", Type: "bool", Setting: true, Help: "skip statuses without a link card (default false)"},
+			{Name: "min_boosts", Type: "int", Setting: true, Help: "skip statuses boosted fewer times (default 0)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if len(spec.Tags)+len(spec.Accounts)+len(spec.Lists) == 0 {
+				return nil, errors.New("one of tags, accounts or lists is required")
+			}
+			return New(spec.Name, spec.Instance, spec.Tags, spec.Accounts, spec.Lists, spec.Icon), nil
+		},
+	})
+}
+
+func (s *Source) Name() string       { return s.name }
+func (s *Source) Icon() string       { return s.icon }
+func (s *Source) TTL() time.Duration { return 15 * time.Minute }
+
+func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	maxItems := cfg.Int("max", 10)
+	linksOnly := cfg.Bool("links_only", false)
+	minBoosts := cfg.Int("min_boosts", 0)
+
+	// Read in configuration order, so a status in several timelines is
+	// always labelled by the same one.
+	type timeline struct{ label, path string }
+	var timelines []timeline
+	for _, tag := range s.hashtags {
+		tag = strings.TrimPrefix(tag, "#")
+		timelines = append(timelines, timeline{"#" + tag, "/api/v1/timelines/tag/" + url.PathEscape(tag)})
+	}
+	for _, id := range s.lists {
+		timelines = append(timelines, timeline{"list " + id, "/api/v1/timelines/list/" + url.PathEscape(id)})
+	}
+	var errs []error
+	for _, acct := range s.accounts {
+		id, err := s.lookupAccount(ctx, acct)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("@%s: %w", acct, err))
+			continue
+		}
+		timelines = append(timelines, timeline{"@" + acct, "/api/v1/accounts/" + url.PathEscape(id) + "/statuses?exclude_replies=true"})
+	}
+	if len(timelines) == 0 && len(errs) == 0 {
+		return nil, errors.New("mastodon: no hashtags, accounts or lists configured")
+	}
+
+	seen := map[string]bool{}
+	var items []source.Item
+	for _, tl := range timelines {
+		statuses, err := s.fetchTimeline(ctx, tl.path, maxItems)
+		switch {
+		case errors.Is(err, source.ErrNotModified):
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", tl.label, err))
+			continue
+		}
+		for _, st := range statuses {
+			// Boosts carry the original status; rank and link that one.
+			if st.Reblog != nil {
+				st = *st.Reblog
+			}
+			if seen[st.ID] || st.InReplyToID != nil {
+				continue
+			}
+			if linksOnly && (st.Card == nil || st.Card.URL == "") {
+				continue
+			}
+			if st.ReblogsCount < minBoosts {
+				continue
+			}
+			seen[st.ID] = true
+			items = append(items, s.toItem(st, tl.label))
+		}
+	}
+
+	// Only fail the sync when no timeline could be read.
+	if len(errs) > 0 && len(items) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp.After(items[j].Timestamp)
+	})
+	if len(items) > maxItems {
+		items = items[:maxItems]
+	}
+
+	return &source.Section{
+		Name:     s.name,
+		Icon:     s.icon,
+		Priority: 45,
+		Items:    items,
+	}, nil
+}
+
+func (s *Source) toItem(st Status, timeline string) source.Item {
+	timestamp, _ := time.Parse(time.RFC3339, st.CreatedAt)
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	text := sanitize.StripHTML(st.Content)
+	if st.SpoilerText != "" {
+		text = "CW: " + sanitize.Text(st.SpoilerText) + "\n\n" + text
+	}
+
+	// The linked page is the story; the status is the discussion. Using the
+	// card URL lets the same link from HN or Lobsters dedupe against it.
+	itemURL := st.URL
+	title := sanitize.FirstLine(text, 100)
+	if st.Card != nil && st.Card.URL != "" {
+		itemURL = st.Card.URL
+		if t := strings.TrimSpace(st.Card.Title); t != "" {
+			title = sanitize.Text(t)
+		}
+	}
+	if itemURL == "" {
+		itemURL = st.URI
+	}
+
+	// Boosts spread a post further than favourites do, so weigh them double.
+	points := st.FavouritesCount + 2*st.ReblogsCount
+	priority := source.Low
+	switch {
+	case points >= 500:
+		priority = source.Urgent
+	case points >= 100:
+		priority = source.High
+	case points >= 20:
+		priority = source.Medium
+	}
+
+	tags := make([]string, 0, len(st.Tags))
+	for _, t := range st.Tags {
+		tags = append(tags, strings.ToLower(t.Name))
+	}
+
+	author := "@" + st.Account.Acct
+	if !strings.Contains(st.Account.Acct, "@") {
+		author += "@" + s.instance
+	}
+
+	return source.Item{
+		ID:        "mastodon-" + st.ID,
+		Title:     title,
+		Subtitle:  fmt.Sprintf("%s • %s", author, timeline),
+		Body:      text,
+		URL:       itemURL,
+		Timestamp: timestamp,
+		Priority:  priority,
+		Category:  "discussion",
+		Icon:      s.icon,
+		Actions: []source.Action{
+			{Key: "o", Label: "open", Command: itemURL},
+			{Key: "c", Label: "thread", Command: st.URL},
+		},
+		Metadata: map[string]any{
+			"points":       points,
+			"comments":     st.RepliesCount,
+			"boosts":       st.ReblogsCount,
+			"favourites":   st.FavouritesCount,
+			"author":       author,
+			"tags":         tags,
+			"comments_url": st.URL,
+		},
+	}
+}
+
+// lookupAccount resolves acct to its account ID. The request is
+// deliberately not conditional: a 304 would leave the ID unknown and the
+// account's timeline unread.
+func (s *Source) lookupAccount(ctx context.Context, acct string) (string, error) {
+	reqURL := s.apiURL("/api/v1/accounts/lookup?acct=" + url.QueryEscape(strings.TrimPrefix(acct, "@")))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header = s.header()
+	resp, err := source.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("mastodon: status %d", resp.StatusCode)
+	}
+
+	var account Account
+	if err := json.NewDecoder(resp.Body).Decode(&account); err != nil {
+		return "", err
+	}
+	return account.ID, nil
+}
+
+func (s *Source) fetchTimeline(ctx context.Context, path string, limit int) ([]Status, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	reqURL := s.apiURL(fmt.Sprintf("%s%slimit=%d", path, sep, min(limit*2, 40)))
+	resp, err := source.DefaultClient.Get(ctx, reqURL, s.header())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mastodon: status %d", resp.StatusCode)
+	}
+
+	var statuses []Status
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func (s *Source) apiURL(path string) string {
+	return "https://" + s.instance + path
+}
+
+// header authenticates with MASTODON_TOKEN when it is set.
+func (s *Source) header() http.Header {
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	if token := os.Getenv("MASTODON_TOKEN"); token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return header
+}
@@ -0,0 +1,222 @@
+// Package podcast provides a podcast feed source that keeps episode
+// enclosures and show notes.
+package podcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+	"github.com/mmcdole/gofeed"
+)
+
+// maxNotes caps the show notes kept in an item body.
+const maxNotes = 4000
+
+// Source fetches episodes from a podcast RSS feed.
+//
+// Settings:
+//
+//	max     number of episodes (default 5)
+//	player  command the play action hands the enclosure URL to (default "mpv")
+type Source struct {
+	name string
+	url  string
+	icon string
+}
+
+// New creates a podcast source for the feed at url.
+func New(name, url, icon string) *Source {
+	if icon == "" {
+		icon = "🎧"
+	}
+	return &Source{
+		name: name,
+		url:  url,
+		icon: icon,
+	}
+}
+
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "podcast",
+		Help: "podcast episodes with enclosures and show notes",
+		Fields: []source.Field{
+			{Name: "feed_url", Type: "string", Required: true, Help: "podcast RSS URL"},
+			source.MaxField(5),
+			{Name: "player", Type: "string", Setting: true, Help: "command the play action runs (default mpv)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if spec.FeedURL == "" {
+				return nil, errors.New("feed_url is required")
+			}
+			return New(spec.Name, spec.FeedURL, spec.Icon), nil
+		},
+	})
+}
+
+func (s *Source) Name() string       { return s.name }
+func (s *Source) Icon() string       { return s.icon }
+func (s *Source) TTL() time.Duration { return time.Hour }
+
+func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	resp, err := source.DefaultClient.Get(ctx, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("podcast: status %d", resp.StatusCode)
+	}
+
+	feed, err := gofeed.NewParser().Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	maxItems := cfg.Int("max", 5)
+	player := cfg.String("player", "mpv")
+
+	items := make([]source.Item, 0, maxItems)
+	for _, entry := range feed.Items {
+		if len(items) >= maxItems {
+			break
+		}
+		items = append(items, s.toItem(feed, entry, player))
+	}
+
+	return &source.Section{
+		Name:     s.name,
+		Icon:     s.icon,
+		Priority: 55,
+		Items:    items,
+	}, nil
+}
+
+func (s *Source) toItem(feed *gofeed.Feed, entry *gofeed.Item, player string) source.Item {
+	timestamp := time.Now()
+	if entry.PublishedParsed != nil {
+		timestamp = *entry.PublishedParsed
+	} else if entry.UpdatedParsed != nil {
+		timestamp = *entry.UpdatedParsed
+	}
+
+	priority := source.Low
+	if time.Since(timestamp) < 24*time.Hour {
+		priority = source.Medium
+	}
+
+	meta := map[string]any{"show": feed.Title}
+	var audio string
+	for _, enc := range entry.Enclosures {
+		if enc.URL == "" {
+			continue
+		}
+		audio = enc.URL
+		meta["enclosure_url"] = enc.URL
+		meta["enclosure_type"] = enc.Type
+		if n, err := strconv.Atoi(enc.Length); err == nil && n > 0 {
+			meta["enclosure_bytes"] = n
+		}
+		break
+	}
+
+	var summary, subtitle string
+	if it := entry.ITunesExt; it != nil {
+		if secs, ok := ParseDuration(it.Duration); ok {
+			meta["duration"] = secs
+		}
+		if n, err := strconv.Atoi(it.Episode); err == nil {
+			meta["episode"] = n
+		}
+		if n, err := strconv.Atoi(it.Season); err == nil {
+			meta["season"] = n
+		}
+		if it.Image != "" {
+			meta["image"] = it.Image
+		}
+		summary, subtitle = it.Summary, it.Subtitle
+	}
+
+	// Prefer full show notes; descriptions are often a truncated copy.
+	notes := entry.Content
+	for _, alt := range []string{entry.Description, summary} {
+		if len(alt) > len(notes) {
+			notes = alt
+		}
+	}
+	notes = sanitize.StripHTML(notes)
+	if r := []rune(notes); len(r) > maxNotes {
+		notes = string(r[:maxNotes]) + "\n…"
+	}
+
+	if subtitle == "" {
+		subtitle = feed.Title
+		if ep, ok := meta["episode"].(int); ok {
+			subtitle = fmt.Sprintf("%s • episode %d", feed.Title, ep)
+		}
+	}
+
+	link := entry.Link
+	if link == "" {
+		link = audio
+	}
+
+	actions := []source.Action{
+		{Key: "o", Label: "open", Command: link},
+	}
+	if audio != "" {
+		actions = append(actions, source.Action{Key: "p", Label: "play", Command: player + " " + shellQuote(audio)})
+	}
+
+	id := entry.GUID
+	if id == "" {
+		id = audio
+	}
+
+	return source.Item{
+		ID:        id,
+		Title:     sanitize.Text(entry.Title),
+		Subtitle:  sanitize.Text(subtitle),
+		Body:      notes,
+		URL:       link,
+		Timestamp: timestamp,
+		Priority:  priority,
+		Category:  "podcast",
+		Icon:      s.icon,
+		Actions:   actions,
+		Metadata:  meta,
+	}
+}
+
+// ParseDuration parses an itunes:duration, which feeds write as seconds,
+// MM:SS or HH:MM:SS, into seconds.
+func ParseDuration(s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	secs := 0
+	for _, part := range strings.Split(s, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		secs = secs*60 + n
+	}
+	return secs, secs > 0
+}
+
+// shellQuote quotes s as one word for a POSIX shell, since an action's
+// command is run as a shell command line.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -4,10 +4,17 @@ package reddit
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jcornudella/hotbrew/pkg/profile"
 	"github.com/jcornudella/hotbrew/pkg/source"
 )
 
@@ -33,39 +40,142 @@ type redditPost struct {
 	CreatedUTC    float64 `json:"created_utc"`
 	LinkFlairText string  `json:"link_flair_text"`
 	IsSelf        bool    `json:"is_self"`
+	Over18        bool    `json:"over_18"`
+	Stickied      bool    `json:"stickied"`
 	Domain        string  `json:"domain"`
 	Ups           int     `json:"ups"`
 }
 
+// Sorts are the listing orders Reddit offers.
+var Sorts = []string{"hot", "top", "new", "rising"}
+
+// windows are the time ranges the top listing accepts.
+var windows = []string{"hour", "day", "week", "month", "year", "all"}
+
 // Source fetches posts from one or more subreddits.
+//
+// It reads the public JSON endpoints, which Reddit rate-limits hard. When
+// REDDIT_CLIENT_ID and REDDIT_CLIENT_SECRET are set it authenticates as an
+// app instead (client credentials, no user) and reads oauth.reddit.com; the
+// bearer token is cached until it expires or Reddit rejects it.
+//
+// Settings:
+//
+//	max            number of posts (default 8)
+//	sort           listing order, one of Sorts (default the one passed to New)
+//	t              time window for sort: top, one of hour, day, week, month, year, all (default day)
+//	min_score      skip posts scoring below this (default 2)
+//	flair          only keep posts with one of these flairs
+//	exclude_flair  skip posts with one of these flairs
+//	nsfw           keep posts marked NSFW (default false)
 type Source struct {
 	name       string
 	icon       string
 	subreddits []string
-	sort       string // "hot", "top", "new"
+	sort       string // default for the sort setting, one of Sorts
 }
 
-// New creates a Reddit source for the given subreddits.
-func New(name string, subreddits []string, icon string) *Source {
+// New creates a Reddit source for the given subreddits. An empty sort
+// means "hot".
+func New(name string, subreddits []string, sort, icon string) *Source {
 	if icon == "" {
 		icon = "🤖"
 	}
+	if sort == "" {
+		sort = "hot"
+	}
 	return &Source{
 		name:       name,
 		icon:       icon,
 		subreddits: subreddits,
-		sort:       "hot",
+		sort:       sort,
 	}
 }
 
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "reddit",
+		Help: "posts from subreddits",
+		Fields: []source.Field{
+			{Name: "subreddits", Type: "strings", Required: true, Help: "subreddit names without r/"},
+			source.MaxField(8),
+			{Name: "sort", Type: "string", Setting: true, Help: "hot, top, new or rising (default hot)"},
+			{Name: "t", Type: "string", Setting: true, Help: "window for sort: top, hour to all (default day)"},
+			{Name: "min_score", Type: "int", Setting: true, Help: "skip posts scoring below this (default 2)"},
+			{Name: "flair", Type: "strings", Setting: true, Help: "only keep posts with these flairs"},
+			{Name: "exclude_flair", Type: "strings", Setting: true, Help: "skip posts with these flairs"},
+			{Name: "nsfw", Type: "bool", Setting: true, Help: "keep posts marked NSFW (default false)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if len(spec.Subreddits) == 0 {
+				return nil, errors.New("subreddits is required")
+			}
+			settings := source.Config{Settings: spec.Settings}
+			sort := settings.String("sort", "hot")
+			if !slices.Contains(Sorts, sort) {
+				return nil, fmt.Errorf("unknown sort %q (want one of %s)", sort, strings.Join(Sorts, ", "))
+			}
+			if t := settings.String("t", "day"); !slices.Contains(windows, t) {
+				return nil, fmt.Errorf("unknown t %q (want one of %s)", t, strings.Join(windows, ", "))
+			}
+			return New(spec.Name, spec.Subreddits, sort, spec.Icon), nil
+		},
+	})
+}
+
 func (s *Source) Name() string        { return s.name }
 func (s *Source) Icon() string        { return s.icon }
 func (s *Source) TTL() time.Duration  { return 15 * time.Minute }
 
+// filter holds the settings that decide which posts are kept.
+type filter struct {
+	minScore int
+	flair    []string
+	exclude  []string
+	nsfw     bool
+}
+
+func (f filter) keep(post redditPost) bool {
+	// Stickied mod posts tend to be low-value.
+	if post.Stickied || post.Score < f.minScore {
+		return false
+	}
+	if post.Over18 && !f.nsfw {
+		return false
+	}
+	if len(f.flair) > 0 && !containsFold(f.flair, post.LinkFlairText) {
+		return false
+	}
+	return !containsFold(f.exclude, post.LinkFlairText)
+}
+
+func containsFold(list []string, s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, e := range list {
+		if strings.EqualFold(strings.TrimSpace(e), strings.TrimSpace(s)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
-	maxItems := 8
-	if max, ok := cfg.Settings["max"].(int); ok {
-		maxItems = max
+	maxItems := cfg.Int("max", 8)
+	sort := cfg.String("sort", s.sort)
+	if !slices.Contains(Sorts, sort) {
+		return nil, fmt.Errorf("reddit: unknown sort %q (want one of %s)", sort, strings.Join(Sorts, ", "))
+	}
+	window := cfg.String("t", "day")
+	if !slices.Contains(windows, window) {
+		return nil, fmt.Errorf("reddit: unknown t %q (want one of %s)", window, strings.Join(windows, ", "))
+	}
+	f := filter{
+		minScore: cfg.Int("min_score", 2),
+		flair:    cfg.Strings("flair"),
+		exclude:  cfg.Strings("exclude_flair"),
+		nsfw:     cfg.Bool("nsfw", false),
 	}
 
 	maxPerSub := maxItems
@@ -74,15 +184,32 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 	}
 
 	var allItems []source.Item
+	var errs []error
+	read, notModified := 0, 0
 
 	for _, sub := range s.subreddits {
-		items, err := s.fetchSubreddit(ctx, sub, maxPerSub)
-		if err != nil {
+		items, err := s.fetchSubreddit(ctx, sub, sort, window, maxPerSub, f)
+		switch {
+		case errors.Is(err, source.ErrNotModified):
+			notModified++
+			continue
+		case err != nil:
+			errs = append(errs, err)
 			continue // skip failed subs, don't fail the whole source
 		}
+		read++
 		allItems = append(allItems, items...)
 	}
 
+	// Only fail the sync when no subreddit could be read, and only report
+	// it unchanged when none failed.
+	if len(errs) > 0 && read == 0 {
+		return nil, errors.Join(errs...)
+	}
+	if notModified == len(s.subreddits) {
+		return nil, source.ErrNotModified
+	}
+
 	// Trim to max.
 	if len(allItems) > maxItems {
 		allItems = allItems[:maxItems]
@@ -96,18 +223,20 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 	}, nil
 }
 
-func (s *Source) fetchSubreddit(ctx context.Context, subreddit string, limit int) ([]source.Item, error) {
-	url := fmt.Sprintf("https://www.reddit.com/r/%s/%s.json?limit=%d&raw_json=1",
-		subreddit, s.sort, limit)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
+func (s *Source) fetchSubreddit(ctx context.Context, subreddit, sort, window string, limit int, f filter) ([]source.Item, error) {
+	// Ask for more than we keep so the filters have something to work with.
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(min(limit*3, 100)))
+	query.Set("raw_json", "1")
+	if sort == "top" {
+		query.Set("t", window)
 	}
-	// Reddit requires a descriptive User-Agent, blocks generic ones.
-	req.Header.Set("User-Agent", "hotbrew:v1.0 (terminal-rss)")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := getListing(ctx, subreddit, sort, query)
+	if errors.Is(err, errTokenRejected) {
+		// The token was revoked before it expired; try once with a new one.
+		resp, err = getListing(ctx, subreddit, sort, query)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -126,10 +255,12 @@ func (s *Source) fetchSubreddit(ctx context.Context, subreddit string, limit int
 	for _, child := range listing.Data.Children {
 		post := child.Data
 
-		// Skip stickied/pinned mod posts (they tend to be low-value).
-		if post.Score < 2 {
+		if !f.keep(post) {
 			continue
 		}
+		if len(items) >= limit {
+			break
+		}
 
 		timestamp := time.Unix(int64(post.CreatedUTC), 0)
 
@@ -190,3 +321,83 @@ func (s *Source) fetchSubreddit(ctx context.Context, subreddit string, limit int
 
 	return items, nil
 }
+
+// errTokenRejected is returned by getListing when Reddit answers 401 to
+// a request made with the cached app token, which it then discards.
+var errTokenRejected = errors.New("reddit: access token rejected")
+
+// getListing requests a subreddit listing, through the OAuth API when an
+// app token is configured.
+func getListing(ctx context.Context, subreddit, sort string, query url.Values) (*http.Response, error) {
+	// Reddit requires a descriptive User-Agent, blocks generic ones.
+	header := http.Header{}
+	header.Set("User-Agent", "hotbrew:v1.0 (terminal-rss)")
+
+	reqURL := fmt.Sprintf("https://www.reddit.com/r/%s/%s.json?%s", subreddit, sort, query.Encode())
+	token, err := appToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		reqURL = fmt.Sprintf("https://oauth.reddit.com/r/%s/%s?%s", subreddit, sort, query.Encode())
+		header.Set("Authorization", "bearer "+token)
+	}
+
+	resp, err := source.DefaultClient.Get(ctx, reqURL, header)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" && resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		source.DefaultClient.InvalidateToken(appTokenKey())
+		return nil, errTokenRejected
+	}
+	return resp, nil
+}
+
+// appTokenKey is the key app tokens are cached under.
+func appTokenKey() string {
+	return "reddit:" + os.Getenv("REDDIT_CLIENT_ID")
+}
+
+// appToken returns an app-only OAuth token when REDDIT_CLIENT_ID and
+// REDDIT_CLIENT_SECRET are set, and "" when they aren't.
+func appToken(ctx context.Context) (string, error) {
+	id, secret := os.Getenv("REDDIT_CLIENT_ID"), os.Getenv("REDDIT_CLIENT_SECRET")
+	if id == "" || secret == "" {
+		return "", nil
+	}
+	return source.DefaultClient.Token(ctx, appTokenKey(), func(ctx context.Context) (string, time.Time, error) {
+		form := url.Values{"grant_type": {"client_credentials"}}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			"https://www.reddit.com/api/v1/access_token", strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		req.SetBasicAuth(id, secret)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("User-Agent", "hotbrew:v1.0 (terminal-rss)")
+
+		resp, err := source.DefaultClient.Do(req)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", time.Time{}, fmt.Errorf("reddit: access token: status %d", resp.StatusCode)
+		}
+
+		var body struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int    `json:"expires_in"`
+			Error       string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return "", time.Time{}, err
+		}
+		if body.AccessToken == "" {
+			return "", time.Time{}, fmt.Errorf("reddit: access token: %s", body.Error)
+		}
+		return body.AccessToken, time.Now().Add(time.Duration(body.ExpiresIn) * time.Second), nil
+	})
+}
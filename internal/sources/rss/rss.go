@@ -3,8 +3,12 @@ package rss
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/jcornudella/hotbrew/pkg/profile"
 	"github.com/jcornudella/hotbrew/pkg/source"
 	"github.com/mmcdole/gofeed"
 )
@@ -14,10 +18,11 @@ type Source struct {
 	name string
 	url  string
 	icon string
+	tags []string // attached to every item, e.g. OPML categories
 }
 
 // New creates a new RSS source
-func New(name, url, icon string) *Source {
+func New(name, url, icon string, tags []string) *Source {
 	if icon == "" {
 		icon = "📰"
 	}
@@ -25,26 +30,50 @@ func New(name, url, icon string) *Source {
 		name: name,
 		url:  url,
 		icon: icon,
+		tags: tags,
 	}
 }
 
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "rss",
+		Help: "an RSS or Atom feed",
+		Fields: []source.Field{
+			{Name: "feed_url", Type: "string", Required: true, Help: "feed URL"},
+			{Name: "tags", Type: "strings", Help: "tags attached to every item"},
+			source.MaxField(5),
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if spec.FeedURL == "" {
+				return nil, errors.New("feed_url is required")
+			}
+			return New(spec.Name, spec.FeedURL, spec.Icon, spec.Tags), nil
+		},
+	})
+}
+
 func (s *Source) Name() string { return s.name }
 func (s *Source) Icon() string { return s.icon }
 func (s *Source) TTL() time.Duration { return 15 * time.Minute }
 
 func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
-	parser := gofeed.NewParser()
+	resp, err := source.DefaultClient.Get(ctx, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-	feed, err := parser.ParseURLWithContext(s.url, ctx)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rss: status %d", resp.StatusCode)
+	}
+
+	feed, err := gofeed.NewParser().Parse(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get max items from config, default to 5
-	maxItems := 5
-	if max, ok := cfg.Settings["max"].(int); ok {
-		maxItems = max
-	}
+	maxItems := cfg.Int("max", 5)
 
 	items := make([]source.Item, 0, maxItems)
 	for i, entry := range feed.Items {
@@ -69,6 +98,11 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 			priority = source.Medium
 		}
 
+		var meta map[string]any
+		if len(s.tags) > 0 {
+			meta = map[string]any{"tags": append([]string(nil), s.tags...)}
+		}
+
 		items = append(items, source.Item{
 			ID:        entry.GUID,
 			Title:     entry.Title,
@@ -81,6 +115,7 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 			Actions: []source.Action{
 				{Key: "o", Label: "open", Command: entry.Link},
 			},
+			Metadata: meta,
 		})
 	}
 
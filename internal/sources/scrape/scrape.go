@@ -0,0 +1,248 @@
+// Package scrape provides a source that extracts items from an HTML page
+// with CSS selectors, for sites that publish no feed.
+package scrape
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+)
+
+// dateLayouts are tried in order when no date_layout setting is given.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// Selectors locate the parts of each item on the page. Title, link, date
+// and summary are matched inside each Item element.
+type Selectors struct {
+	Item    string
+	Title   string
+	Link    string // defaults to the title's own href, then the first a[href]
+	Date    string // a datetime attribute is preferred over the text
+	Summary string
+}
+
+// Source scrapes items from one page.
+//
+// Settings:
+//
+//	max          number of items (default 10)
+//	date_layout  Go time layout for dates, e.g. "02/01/2006" (default: common formats)
+type Source struct {
+	name string
+	url  string
+	icon string
+	sel  Selectors
+}
+
+// New creates a source that scrapes pageURL.
+func New(name, pageURL string, sel Selectors, icon string) *Source {
+	if icon == "" {
+		icon = "🕸️"
+	}
+	return &Source{
+		name: name,
+		url:  pageURL,
+		icon: icon,
+		sel:  sel,
+	}
+}
+
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "html",
+		Help: "items scraped from a web page with CSS selectors",
+		Fields: []source.Field{
+			{Name: "page_url", Type: "string", Required: true, Help: "page to scrape"},
+			{Name: "selectors.item", Type: "string", Required: true, Help: "one element per item"},
+			{Name: "selectors.title", Type: "string", Required: true, Help: "title, inside the item"},
+			{Name: "selectors.link", Type: "string", Help: "link, inside the item (default the title's href)"},
+			{Name: "selectors.date", Type: "string", Help: "date, inside the item"},
+			{Name: "selectors.summary", Type: "string", Help: "summary, inside the item"},
+			source.MaxField(10),
+			{Name: "date_layout", Type: "string", Setting: true, Help: "Go time layout for dates (default: common formats)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if spec.PageURL == "" {
+				return nil, errors.New("page_url is required")
+			}
+			sel := Selectors{
+				Item:    spec.Selectors["item"],
+				Title:   spec.Selectors["title"],
+				Link:    spec.Selectors["link"],
+				Date:    spec.Selectors["date"],
+				Summary: spec.Selectors["summary"],
+			}
+			if sel.Item == "" || sel.Title == "" {
+				return nil, errors.New("selectors.item and selectors.title are required")
+			}
+			return New(spec.Name, spec.PageURL, sel, spec.Icon), nil
+		},
+	})
+}
+
+func (s *Source) Name() string       { return s.name }
+func (s *Source) Icon() string       { return s.icon }
+func (s *Source) TTL() time.Duration { return time.Hour }
+
+func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	maxItems := cfg.Int("max", 10)
+	layout := cfg.String("date_layout", "")
+
+	base, err := url.Parse(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("html: bad page_url: %w", err)
+	}
+
+	resp, err := source.DefaultClient.Get(ctx, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("html: status %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []source.Item
+	doc.Find(s.sel.Item).EachWithBreak(func(_ int, el *goquery.Selection) bool {
+		if item, ok := s.toItem(el, base, layout); ok {
+			items = append(items, item)
+		}
+		return len(items) < maxItems
+	})
+	if len(items) == 0 {
+		return nil, fmt.Errorf("html: no items matched %q with a %q title", s.sel.Item, s.sel.Title)
+	}
+
+	return &source.Section{
+		Name:     s.name,
+		Icon:     s.icon,
+		Priority: 50,
+		Items:    items,
+	}, nil
+}
+
+func (s *Source) toItem(el *goquery.Selection, base *url.URL, layout string) (source.Item, bool) {
+	titleEl := el.Find(s.sel.Title).First()
+	title := cleanText(titleEl.Text())
+	if title == "" {
+		return source.Item{}, false
+	}
+
+	var href string
+	switch {
+	case s.sel.Link != "":
+		href, _ = el.Find(s.sel.Link).First().Attr("href")
+	case titleEl.Is("a[href]"):
+		href, _ = titleEl.Attr("href")
+	case titleEl.Find("a[href]").Length() > 0:
+		href, _ = titleEl.Find("a[href]").First().Attr("href")
+	case el.Is("a[href]"):
+		href, _ = el.Attr("href")
+	default:
+		href, _ = el.Find("a[href]").First().Attr("href")
+	}
+	link := resolve(base, href)
+
+	var timestamp time.Time
+	if s.sel.Date != "" {
+		dateEl := el.Find(s.sel.Date).First()
+		raw, ok := dateEl.Attr("datetime")
+		if !ok {
+			raw = dateEl.Text()
+		}
+		timestamp = parseDate(strings.TrimSpace(raw), layout)
+	}
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	var summary string
+	if s.sel.Summary != "" {
+		summary = cleanText(el.Find(s.sel.Summary).First().Text())
+	}
+
+	id := link
+	if id == "" || id == s.url {
+		id = s.url + "#" + title
+	}
+
+	var actions []source.Action
+	if link != "" {
+		actions = []source.Action{{Key: "o", Label: "open", Command: link}}
+	}
+
+	return source.Item{
+		ID:        id,
+		Title:     title,
+		Subtitle:  summary,
+		URL:       link,
+		Timestamp: timestamp,
+		Priority:  source.Low,
+		Category:  "news",
+		Icon:      s.icon,
+		Actions:   actions,
+	}, true
+}
+
+// resolve makes href absolute against the page URL.
+func resolve(base *url.URL, href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "javascript:") {
+		return ""
+	}
+	u, err := base.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+// parseDate parses raw with layout, or with the common layouts when layout
+// is empty. It returns the zero time if nothing matches.
+func parseDate(raw, layout string) time.Time {
+	if raw == "" {
+		return time.Time{}
+	}
+	layouts := dateLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l, raw, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// cleanText collapses whitespace and strips control characters.
+func cleanText(s string) string {
+	return sanitize.Text(strings.Join(strings.Fields(s), " "))
+}
@@ -3,8 +3,12 @@ package tldr
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/jcornudella/hotbrew/pkg/profile"
 	"github.com/jcornudella/hotbrew/pkg/source"
 	"github.com/mmcdole/gofeed"
 )
@@ -45,6 +49,23 @@ func NewTech() *Source {
 	return New("TLDR Tech", "https://tldr.tech/api/rss/tech", "💻")
 }
 
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "tldr",
+		Help: "a TLDR newsletter feed",
+		Fields: []source.Field{
+			{Name: "feed_url", Type: "string", Required: true, Help: "newsletter RSS URL"},
+			source.MaxField(8),
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if spec.FeedURL == "" {
+				return nil, errors.New("feed_url is required")
+			}
+			return New(spec.Name, spec.FeedURL, spec.Icon), nil
+		},
+	})
+}
+
 func (s *Source) Name() string        { return s.name }
 func (s *Source) Icon() string        { return s.icon }
 func (s *Source) TTL() time.Duration  { return 30 * time.Minute }
@@ -55,8 +76,17 @@ func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section,
 		maxItems = max
 	}
 
-	parser := gofeed.NewParser()
-	feed, err := parser.ParseURLWithContext(s.url, ctx)
+	resp, err := source.DefaultClient.Get(ctx, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tldr: status %d", resp.StatusCode)
+	}
+
+	feed, err := gofeed.NewParser().Parse(resp.Body)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,343 @@
+// Package youtube provides a YouTube channel and playlist source using the
+// public Atom feeds, which need no API key.
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/sanitize"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/extensions"
+)
+
+const (
+	feedURL   = "https://www.youtube.com/feeds/videos.xml"
+	videosURL = "https://www.googleapis.com/youtube/v3/videos"
+)
+
+// Source fetches recent videos from YouTube channels and playlists.
+//
+// The feeds don't carry video lengths. When YOUTUBE_API_KEY is set, one
+// Data API call per fetch fills them in.
+//
+// Settings:
+//
+//	max          number of videos (default 8)
+//	max_minutes  skip videos longer than this, when the length is known (default 0, no limit)
+type Source struct {
+	name      string
+	icon      string
+	channels  []string // channel IDs, "UC…"
+	playlists []string // playlist IDs, "PL…"
+}
+
+// New creates a YouTube source for the given channel and playlist IDs.
+func New(name string, channels, playlists []string, icon string) *Source {
+	if icon == "" {
+		icon = "📺"
+	}
+	return &Source{
+		name:      name,
+		icon:      icon,
+		channels:  channels,
+		playlists: playlists,
+	}
+}
+
+func init() {
+	source.RegisterDriver(source.Driver{
+		Name: "youtube",
+		Help: "new videos from YouTube channels and playlists",
+		Fields: []source.Field{
+			{Name: "channels", Type: "strings", Help: "channel IDs (UC…)"},
+			{Name: "playlists", Type: "strings", Help: "playlist IDs (PL…)"},
+			source.MaxField(8),
+			{Name: "max_minutes", Type: "int", Setting: true, Help: "skip longer videos when YOUTUBE_API_KEY is set (default no limit)"},
+		},
+		New: func(spec profile.SourceSpec) (source.Source, error) {
+			if len(spec.Channels)+len(spec.Playlists) == 0 {
+				return nil, errors.New("one of channels or playlists is required")
+			}
+			return New(spec.Name, spec.Channels, spec.Playlists, spec.Icon), nil
+		},
+	})
+}
+
+func (s *Source) Name() string       { return s.name }
+func (s *Source) Icon() string       { return s.icon }
+func (s *Source) TTL() time.Duration { return 30 * time.Minute }
+
+func (s *Source) Fetch(ctx context.Context, cfg source.Config) (*source.Section, error) {
+	maxItems := cfg.Int("max", 8)
+	maxMinutes := cfg.Int("max_minutes", 0)
+
+	var feeds []string
+	for _, id := range s.channels {
+		feeds = append(feeds, feedURL+"?channel_id="+url.QueryEscape(id))
+	}
+	for _, id := range s.playlists {
+		feeds = append(feeds, feedURL+"?playlist_id="+url.QueryEscape(id))
+	}
+	if len(feeds) == 0 {
+		return nil, errors.New("youtube: no channels or playlists configured")
+	}
+
+	seen := map[string]bool{}
+	var items []source.Item
+	var errs []error
+	for _, u := range feeds {
+		entries, err := fetchFeed(ctx, u)
+		switch {
+		case errors.Is(err, source.ErrNotModified):
+			continue
+		case err != nil:
+			errs = append(errs, err)
+			continue
+		}
+		for _, entry := range entries {
+			item := s.toItem(entry)
+			id, _ := item.Metadata["video_id"].(string)
+			if id == "" || seen[id] {
+				continue
+			}
+			seen[id] = true
+			items = append(items, item)
+		}
+	}
+
+	// Only fail the sync when no feed could be read.
+	if len(errs) > 0 && len(items) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp.After(items[j].Timestamp)
+	})
+	if len(items) > maxItems*2 {
+		items = items[:maxItems*2]
+	}
+
+	// Lengths are best effort: a failed lookup leaves them unknown.
+	if key := os.Getenv("YOUTUBE_API_KEY"); key != "" {
+		if durations, err := fetchDurations(ctx, key, items); err == nil {
+			for i := range items {
+				if d, ok := durations[items[i].Metadata["video_id"].(string)]; ok {
+					items[i].Metadata["duration"] = int(d.Seconds())
+				}
+			}
+		}
+	}
+
+	kept := items[:0]
+	for _, item := range items {
+		if secs, ok := item.Metadata["duration"].(int); ok && maxMinutes > 0 && secs > maxMinutes*60 {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	items = kept
+	if len(items) > maxItems {
+		items = items[:maxItems]
+	}
+
+	return &source.Section{
+		Name:     s.name,
+		Icon:     s.icon,
+		Priority: 55,
+		Items:    items,
+	}, nil
+}
+
+func (s *Source) toItem(entry *gofeed.Item) source.Item {
+	timestamp := time.Now()
+	if entry.PublishedParsed != nil {
+		timestamp = *entry.PublishedParsed
+	}
+
+	videoID := extValue(entry.Extensions, "yt", "videoId")
+	channelID := extValue(entry.Extensions, "yt", "channelId")
+	channel := ""
+	if entry.Author != nil {
+		channel = entry.Author.Name
+	}
+
+	var thumbnail, description string
+	var views int
+	if groups := entry.Extensions["media"]["group"]; len(groups) > 0 {
+		g := groups[0]
+		if t := g.Children["thumbnail"]; len(t) > 0 {
+			thumbnail = t[0].Attrs["url"]
+		}
+		if d := g.Children["description"]; len(d) > 0 {
+			description = d[0].Value
+		}
+		if c := g.Children["community"]; len(c) > 0 {
+			if st := c[0].Children["statistics"]; len(st) > 0 {
+				views, _ = strconv.Atoi(st[0].Attrs["views"])
+			}
+		}
+	}
+
+	link := entry.Link
+	if link == "" && videoID != "" {
+		link = "https://www.youtube.com/watch?v=" + videoID
+	}
+
+	priority := source.Low
+	if time.Since(timestamp) < 24*time.Hour {
+		priority = source.Medium
+	}
+
+	subtitle := channel
+	if views > 0 {
+		subtitle = fmt.Sprintf("%s • %s views", channel, formatNumber(views))
+	}
+
+	return source.Item{
+		ID:        "yt-" + videoID,
+		Title:     sanitize.Text(entry.Title),
+		Subtitle:  sanitize.Text(subtitle),
+		Body:      sanitize.Text(strings.TrimSpace(description)),
+		URL:       link,
+		Timestamp: timestamp,
+		Priority:  priority,
+		Category:  "video",
+		Icon:      s.icon,
+		Actions: []source.Action{
+			{Key: "o", Label: "watch", Command: link},
+		},
+		Metadata: map[string]any{
+			"video_id":   videoID,
+			"channel":    channel,
+			"channel_id": channelID,
+			"thumbnail":  thumbnail,
+			"views":      views,
+		},
+	}
+}
+
+func fetchFeed(ctx context.Context, u string) ([]*gofeed.Item, error) {
+	resp, err := source.DefaultClient.Get(ctx, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("youtube: status %d for %s", resp.StatusCode, u)
+	}
+
+	feed, err := gofeed.NewParser().Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return feed.Items, nil
+}
+
+// fetchDurations looks up the length of each item's video with the Data API.
+func fetchDurations(ctx context.Context, key string, items []source.Item) (map[string]time.Duration, error) {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.Metadata["video_id"].(string))
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if len(ids) > 50 {
+		ids = ids[:50] // the API's per-request limit
+	}
+
+	params := url.Values{}
+	params.Set("part", "contentDetails")
+	params.Set("id", strings.Join(ids, ","))
+	params.Set("key", key)
+
+	// Deliberately not conditional: the URL carries the API key, which
+	// must not be stored with the validators, and a 304 would leave the
+	// items without durations for max_minutes to filter on.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, videosURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := source.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("youtube: videos API returned %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Items []struct {
+			ID             string `json:"id"`
+			ContentDetails struct {
+				Duration string `json:"duration"`
+			} `json:"contentDetails"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	durations := make(map[string]time.Duration, len(result.Items))
+	for _, v := range result.Items {
+		if d, ok := ParseDuration(v.ContentDetails.Duration); ok {
+			durations[v.ID] = d
+		}
+	}
+	return durations, nil
+}
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)D)?T?(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+// ParseDuration parses the ISO 8601 durations the Data API returns, such
+// as "PT1H2M3S".
+func ParseDuration(s string) (time.Duration, bool) {
+	m := isoDuration.FindStringSubmatch(s)
+	if m == nil || s == "P" || s == "PT" {
+		return 0, false
+	}
+	var d time.Duration
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	for i, unit := range units {
+		if m[i+1] != "" {
+			n, _ := strconv.Atoi(m[i+1])
+			d += time.Duration(n) * unit
+		}
+	}
+	return d, true
+}
+
+// extValue returns the text of the first ns:name extension element.
+func extValue(exts ext.Extensions, ns, name string) string {
+	if e := exts[ns][name]; len(e) > 0 {
+		return e[0].Value
+	}
+	return ""
+}
+
+func formatNumber(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return strconv.Itoa(n)
+	}
+}
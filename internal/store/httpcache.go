@@ -0,0 +1,25 @@
+package store
+
+// HTTPValidators returns the ETag and Last-Modified values saved for url.
+func (s *Store) HTTPValidators(url string) (etag, lastModified string, ok bool) {
+	err := s.db.QueryRow(
+		"SELECT COALESCE(etag,''), COALESCE(last_modified,'') FROM http_cache WHERE url = ?", url,
+	).Scan(&etag, &lastModified)
+	if err != nil {
+		return "", "", false
+	}
+	return etag, lastModified, true
+}
+
+// SaveHTTPValidators records the cache validators from a successful response.
+func (s *Store) SaveHTTPValidators(url, etag, lastModified string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO http_cache (url, etag, last_modified, updated_at)
+		VALUES (?, ?, ?, datetime('now'))
+		ON CONFLICT(url) DO UPDATE SET
+			etag = excluded.etag, last_modified = excluded.last_modified,
+			updated_at = datetime('now')`,
+		url, etag, lastModified,
+	)
+	return err
+}
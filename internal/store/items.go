@@ -9,11 +9,18 @@ import (
 
 // InsertItem stores a TRSS item, skipping duplicates.
 func (s *Store) InsertItem(item trss.Item, sourceID int) error {
+	_, err := s.InsertItemNew(item, sourceID)
+	return err
+}
+
+// InsertItemNew stores a TRSS item and reports whether it was new. Items
+// already in the store are left untouched and reported as not new.
+func (s *Store) InsertItemNew(item trss.Item, sourceID int) (bool, error) {
 	tags, _ := json.Marshal(item.Tags)
 	engagement, _ := json.Marshal(item.Engagement)
 	meta, _ := json.Marshal(item.Meta)
 
-	_, err := s.db.Exec(`
+	result, err := s.db.Exec(`
 		INSERT OR IGNORE INTO items
 			(id, fingerprint, title, url, url_canonical, source_id, source_name,
 			 published_at, fetched_at, summary, body, tags, score_raw, engagement, meta)
@@ -25,7 +32,18 @@ func (s *Store) InsertItem(item trss.Item, sourceID int) error {
 		item.Summary, item.Body, string(tags),
 		item.Score, string(engagement), string(meta),
 	)
-	return err
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// HasItem reports whether an item with the given ID is stored.
+func (s *Store) HasItem(id string) bool {
+	var n int
+	s.db.QueryRow(`SELECT COUNT(*) FROM items WHERE id = ?`, id).Scan(&n)
+	return n > 0
 }
 
 // ItemFilter holds query parameters for listing items.
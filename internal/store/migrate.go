@@ -1,8 +1,14 @@
 package store
 
-import "fmt"
+import (
+	"database/sql"
+	"fmt"
+	"strings"
 
-const currentVersion = 2
+	"github.com/jcornudella/hotbrew/pkg/trss"
+)
+
+const currentVersion = 7
 
 var migrations = []string{
 	// Version 1: initial schema
@@ -91,8 +97,64 @@ var migrations = []string{
 		created_at TEXT NOT NULL DEFAULT (datetime('now'))
 	);
 	`,
+	// Version 3: HTTP cache validators for conditional requests
+	`
+	CREATE TABLE IF NOT EXISTS http_cache (
+		url           TEXT PRIMARY KEY,
+		etag          TEXT,
+		last_modified TEXT,
+		updated_at    TEXT NOT NULL DEFAULT (datetime('now'))
+	);
+	`,
+	// Version 4: per-source circuit breaker
+	`
+	ALTER TABLE sources ADD COLUMN breaker_until TEXT;
+	`,
+	// Version 5: sync run history
+	`
+	CREATE TABLE IF NOT EXISTS sync_runs (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		started_at  TEXT NOT NULL,
+		finished_at TEXT,
+		sources     INTEGER DEFAULT 0,
+		inserted    INTEGER DEFAULT 0,
+		errors      INTEGER DEFAULT 0
+	);
+
+	CREATE TABLE IF NOT EXISTS sync_results (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		run_id      INTEGER NOT NULL REFERENCES sync_runs(id),
+		source_key  TEXT NOT NULL,
+		status      TEXT NOT NULL,
+		started_at  TEXT NOT NULL,
+		finished_at TEXT NOT NULL,
+		fetched     INTEGER DEFAULT 0,
+		inserted    INTEGER DEFAULT 0,
+		duplicates  INTEGER DEFAULT 0,
+		http_status INTEGER,
+		error       TEXT
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_sync_results_run ON sync_results(run_id);
+	CREATE INDEX IF NOT EXISTS idx_sync_results_source ON sync_results(source_key, id);
+	`,
+	// Version 6: cached OAuth access tokens
+	`
+	CREATE TABLE IF NOT EXISTS auth_tokens (
+		key         TEXT PRIMARY KEY,
+		token       TEXT NOT NULL,
+		expires_at  TEXT NOT NULL
+	);
+	`,
 }
 
+// upgrades are data changes SQL can't express, keyed by the version they
+// belong to. Each runs after that version's SQL migration, if any.
+var upgrades = map[int]func(*sql.Tx) error{
+	// Version 7: arXiv URLs canonicalize to the versionless abstract page
+	7: recanonicalizeArxiv,
+}
+
 func (s *Store) migrate() error {
 	// Create version table if needed
 	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)`); err != nil {
@@ -107,21 +169,112 @@ func (s *Store) migrate() error {
 	}
 
 	// Run pending migrations
-	for i := version; i < len(migrations); i++ {
-		if _, err := s.db.Exec(migrations[i]); err != nil {
+	for i := version; i < currentVersion; i++ {
+		if err := s.migrateTo(i + 1); err != nil {
 			return fmt.Errorf("migration %d: %w", i+1, err)
 		}
 	}
 
-	// Update version
-	if version < currentVersion {
-		if _, err := s.db.Exec("DELETE FROM schema_version"); err != nil {
+	return nil
+}
+
+// migrateTo runs one version's migration and records the new version in
+// the same transaction, so a failed step is retried alone next time rather
+// than rerunning the ones before it.
+func (s *Store) migrateTo(version int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if version <= len(migrations) {
+		if _, err := tx.Exec(migrations[version-1]); err != nil {
 			return err
 		}
-		if _, err := s.db.Exec("INSERT INTO schema_version (version) VALUES (?)", currentVersion); err != nil {
+	}
+	if upgrade := upgrades[version]; upgrade != nil {
+		if err := upgrade(tx); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	// Update version
+	if _, err := tx.Exec("DELETE FROM schema_version"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO schema_version (version) VALUES (?)", version); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
+
+// recanonicalizeArxiv moves stored arXiv papers to the versionless
+// canonical URL, and the ID and fingerprint derived from it, so fetching
+// them again is seen as a duplicate rather than a new item. Read state and
+// dedup edges follow the ID. A row whose paper is already stored under the
+// new ID or fingerprint, such as a second version of it, is merged into
+// that row and deleted.
+func recanonicalizeArxiv(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, url_canonical, source_id FROM items WHERE url_canonical LIKE '%arxiv.org/%'`)
+	if err != nil {
+		return err
+	}
+	type change struct {
+		id, canonical string
+		sourceID      int
+	}
+	var changes []change
+	for rows.Next() {
+		var c change
+		if err := rows.Scan(&c.id, &c.canonical, &c.sourceID); err != nil {
+			rows.Close()
+			return err
+		}
+		canonical := trss.CanonicalURL(c.canonical)
+		if !strings.HasPrefix(canonical, "https://arxiv.org/abs/") {
+			continue
+		}
+		if canonical != c.canonical || trss.GenerateID(canonical) != c.id {
+			c.canonical = canonical
+			changes = append(changes, c)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		newID, fingerprint := trss.GenerateID(c.canonical), trss.Fingerprint(c.canonical)
+		if newID == c.id {
+			if _, err := tx.Exec(`UPDATE OR IGNORE items SET url_canonical = ?, fingerprint = ? WHERE id = ?`,
+				c.canonical, fingerprint, c.id); err != nil {
+				return err
+			}
+			continue
+		}
+		var taken int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM items WHERE id = ? OR (fingerprint = ? AND source_id = ? AND id != ?)`,
+			newID, fingerprint, c.sourceID, c.id).Scan(&taken); err != nil {
+			return err
+		}
+		stmts := []string{
+			`UPDATE items SET id = ?2, url_canonical = ?3, fingerprint = ?4 WHERE id = ?1`,
+			`UPDATE OR IGNORE item_state SET item_id = ?2 WHERE item_id = ?1`,
+			`DELETE FROM item_state WHERE item_id = ?1`,
+			`UPDATE OR IGNORE dedup_edges SET item_id_a = ?2 WHERE item_id_a = ?1`,
+			`UPDATE OR IGNORE dedup_edges SET item_id_b = ?2 WHERE item_id_b = ?1`,
+			`DELETE FROM dedup_edges WHERE item_id_a = ?1 OR item_id_b = ?1 OR item_id_a = item_id_b`,
+		}
+		if taken > 0 {
+			stmts[0] = `DELETE FROM items WHERE id = ?1`
+		}
+		for _, stmt := range stmts {
+			if _, err := tx.Exec(stmt, c.id, newID, c.canonical, fingerprint); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
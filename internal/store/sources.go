@@ -17,7 +17,11 @@ type SourceRecord struct {
 	Settings   map[string]any
 	AddedAt    time.Time
 	LastSync   *time.Time
-	SyncErrors int
+	SyncErrors int // consecutive failed syncs
+
+	// BreakerUntil is set while the source's circuit breaker is open:
+	// after repeated failures, syncs skip it until this time.
+	BreakerUntil *time.Time
 }
 
 // InsertSource adds a new source and returns its ID.
@@ -53,12 +57,43 @@ func (s *Store) GetOrCreateSource(name, kind, url, icon string) (int, error) {
 	return s.InsertSource(name, kind, url, icon, nil)
 }
 
+// sourceColumns lists the columns read by scanSource, in order.
+const sourceColumns = `id, name, kind, COALESCE(url,''), icon, weight, enabled,
+	COALESCE(settings,'{}'), added_at, last_sync, sync_errors, breaker_until`
+
+// scanSource reads one row selected with sourceColumns.
+func scanSource(row interface{ Scan(...any) error }) (SourceRecord, error) {
+	var src SourceRecord
+	var settingsJSON, addedAt string
+	var lastSync, breakerUntil *string
+	var enabled int
+
+	err := row.Scan(
+		&src.ID, &src.Name, &src.Kind, &src.URL, &src.Icon,
+		&src.Weight, &enabled, &settingsJSON, &addedAt, &lastSync, &src.SyncErrors,
+		&breakerUntil,
+	)
+	if err != nil {
+		return src, err
+	}
+
+	src.Enabled = enabled == 1
+	src.AddedAt = parseTime(addedAt)
+	json.Unmarshal([]byte(settingsJSON), &src.Settings)
+	if lastSync != nil {
+		t := parseTime(*lastSync)
+		src.LastSync = &t
+	}
+	if breakerUntil != nil {
+		t := parseTime(*breakerUntil)
+		src.BreakerUntil = &t
+	}
+	return src, nil
+}
+
 // ListSources returns all sources.
 func (s *Store) ListSources() ([]SourceRecord, error) {
-	rows, err := s.db.Query(`
-		SELECT id, name, kind, COALESCE(url,''), icon, weight, enabled,
-			COALESCE(settings,'{}'), added_at, last_sync, sync_errors
-		FROM sources ORDER BY id`)
+	rows, err := s.db.Query("SELECT " + sourceColumns + " FROM sources ORDER BY id")
 	if err != nil {
 		return nil, err
 	}
@@ -66,42 +101,54 @@ func (s *Store) ListSources() ([]SourceRecord, error) {
 
 	var sources []SourceRecord
 	for rows.Next() {
-		var src SourceRecord
-		var settingsJSON, addedAt string
-		var lastSync *string
-		var enabled int
-
-		err := rows.Scan(
-			&src.ID, &src.Name, &src.Kind, &src.URL, &src.Icon,
-			&src.Weight, &enabled, &settingsJSON, &addedAt, &lastSync, &src.SyncErrors,
-		)
+		src, err := scanSource(rows)
 		if err != nil {
 			continue
 		}
-
-		src.Enabled = enabled == 1
-		src.AddedAt, _ = time.Parse(time.RFC3339, addedAt)
-		json.Unmarshal([]byte(settingsJSON), &src.Settings)
-		if lastSync != nil {
-			t, _ := time.Parse(time.RFC3339, *lastSync)
-			src.LastSync = &t
-		}
-
 		sources = append(sources, src)
 	}
 
 	return sources, rows.Err()
 }
 
-// UpdateLastSync updates the last sync time for a source.
+// GetSource returns the source with the given ID.
+func (s *Store) GetSource(id int) (*SourceRecord, error) {
+	src, err := scanSource(s.db.QueryRow("SELECT "+sourceColumns+" FROM sources WHERE id = ?", id))
+	if err != nil {
+		return nil, err
+	}
+	return &src, nil
+}
+
+// parseTime reads timestamps written either by Go (RFC 3339) or by
+// SQLite's datetime('now'), which uses "YYYY-MM-DD HH:MM:SS" in UTC.
+func parseTime(value string) time.Time {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	t, _ := time.Parse(time.DateTime, value)
+	return t
+}
+
+// UpdateLastSync updates the last sync time for a source and closes its
+// circuit breaker.
 func (s *Store) UpdateLastSync(sourceID int) error {
 	_, err := s.db.Exec(
-		"UPDATE sources SET last_sync = datetime('now'), sync_errors = 0 WHERE id = ?",
+		"UPDATE sources SET last_sync = datetime('now'), sync_errors = 0, breaker_until = NULL WHERE id = ?",
 		sourceID,
 	)
 	return err
 }
 
+// OpenBreaker stops syncs of a source until the given time.
+func (s *Store) OpenBreaker(sourceID int, until time.Time) error {
+	_, err := s.db.Exec(
+		"UPDATE sources SET breaker_until = ? WHERE id = ?",
+		until.UTC().Format(time.RFC3339), sourceID,
+	)
+	return err
+}
+
 // IncrSyncErrors increments the error count for a source.
 func (s *Store) IncrSyncErrors(sourceID int) error {
 	_, err := s.db.Exec(
@@ -30,7 +30,9 @@ func Open(dbPath string) (*Store, error) {
 		os.Chmod(dbPath, 0o600)
 	}
 
-	db, err := sql.Open("sqlite", dbPath+"?_journal_mode=WAL&_busy_timeout=5000")
+	// Pragmas in the DSN apply to every pooled connection; concurrent
+	// fetchers write HTTP validators, so each needs a busy timeout.
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
 	if err != nil {
 		return nil, err
 	}
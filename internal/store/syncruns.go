@@ -0,0 +1,133 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SyncRun summarizes one `hotbrew sync` or daemon cycle.
+type SyncRun struct {
+	ID         int
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Sources    int
+	Inserted   int
+	Errors     int
+}
+
+// SyncResultRecord is the outcome of syncing one source during a run.
+type SyncResultRecord struct {
+	RunID      int
+	SourceKey  string
+	Status     string // "ok", "not_modified", "skipped" or "error"
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Fetched    int
+	Inserted   int
+	Duplicates int
+	HTTPStatus int // 0 when no HTTP response was received
+	Error      string
+}
+
+// StartSyncRun records the start of a sync run and returns its ID.
+func (s *Store) StartSyncRun(started time.Time) (int, error) {
+	result, err := s.db.Exec(
+		"INSERT INTO sync_runs (started_at) VALUES (?)",
+		started.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+// FinishSyncRun closes a run, totalling its per-source results.
+func (s *Store) FinishSyncRun(runID int, finished time.Time) error {
+	_, err := s.db.Exec(`
+		UPDATE sync_runs SET
+			finished_at = ?,
+			sources  = (SELECT COUNT(*) FROM sync_results WHERE run_id = ?),
+			inserted = (SELECT COALESCE(SUM(inserted), 0) FROM sync_results WHERE run_id = ?),
+			errors   = (SELECT COUNT(*) FROM sync_results WHERE run_id = ? AND status = 'error')
+		WHERE id = ?`,
+		finished.UTC().Format(time.RFC3339), runID, runID, runID, runID,
+	)
+	return err
+}
+
+// InsertSyncResult records the outcome of syncing one source.
+func (s *Store) InsertSyncResult(r SyncResultRecord) error {
+	var httpStatus any
+	if r.HTTPStatus > 0 {
+		httpStatus = r.HTTPStatus
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO sync_results
+			(run_id, source_key, status, started_at, finished_at,
+			 fetched, inserted, duplicates, http_status, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.RunID, r.SourceKey, r.Status,
+		r.StartedAt.UTC().Format(time.RFC3339),
+		r.FinishedAt.UTC().Format(time.RFC3339),
+		r.Fetched, r.Inserted, r.Duplicates, httpStatus, r.Error,
+	)
+	return err
+}
+
+// ListSyncRuns returns the most recent runs, newest first.
+func (s *Store) ListSyncRuns(limit int) ([]SyncRun, error) {
+	rows, err := s.db.Query(`
+		SELECT id, started_at, finished_at, sources, inserted, errors
+		FROM sync_runs ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []SyncRun
+	for rows.Next() {
+		var r SyncRun
+		var started string
+		var finished sql.NullString
+		if err := rows.Scan(&r.ID, &started, &finished, &r.Sources, &r.Inserted, &r.Errors); err != nil {
+			continue
+		}
+		r.StartedAt = parseTime(started)
+		if finished.Valid {
+			t := parseTime(finished.String)
+			r.FinishedAt = &t
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+// SyncResultsSince returns per-source results recorded at or after since,
+// newest first.
+func (s *Store) SyncResultsSince(since time.Time) ([]SyncResultRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT run_id, source_key, status, started_at, finished_at,
+			fetched, inserted, duplicates, COALESCE(http_status, 0), COALESCE(error, '')
+		FROM sync_results WHERE started_at >= ? ORDER BY id DESC`,
+		since.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SyncResultRecord
+	for rows.Next() {
+		var r SyncResultRecord
+		var started, finished string
+		if err := rows.Scan(&r.RunID, &r.SourceKey, &r.Status, &started, &finished,
+			&r.Fetched, &r.Inserted, &r.Duplicates, &r.HTTPStatus, &r.Error); err != nil {
+			continue
+		}
+		r.StartedAt = parseTime(started)
+		r.FinishedAt = parseTime(finished)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
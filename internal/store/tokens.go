@@ -0,0 +1,27 @@
+package store
+
+import "time"
+
+// AuthToken returns the access token saved under key and when it expires.
+func (s *Store) AuthToken(key string) (token string, expires time.Time, ok bool) {
+	var expiresAt string
+	err := s.db.QueryRow(
+		"SELECT token, expires_at FROM auth_tokens WHERE key = ?", key,
+	).Scan(&token, &expiresAt)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return token, parseTime(expiresAt), true
+}
+
+// SaveAuthToken records an access token, replacing any saved under key.
+func (s *Store) SaveAuthToken(key, token string, expires time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO auth_tokens (key, token, expires_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			token = excluded.token, expires_at = excluded.expires_at`,
+		key, token, expires.UTC().Format(time.RFC3339),
+	)
+	return err
+}
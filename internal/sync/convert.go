@@ -8,6 +8,11 @@ import (
 	"github.com/jcornudella/hotbrew/pkg/trss"
 )
 
+// engagementKeys are the metadata keys copied into a TRSS item's engagement.
+// Scoring reads points, stars and comments; social drivers also record the
+// raw counts their points are made of.
+var engagementKeys = []string{"points", "comments", "stars", "boosts", "favourites", "likes", "reposts", "answers", "accepted"}
+
 // ConvertItem transforms a source.Item into a trss.Item.
 func ConvertItem(item source.Item, src source.Source) trss.Item {
 	canonical := trss.CanonicalURL(item.URL)
@@ -29,15 +34,9 @@ func ConvertItem(item source.Item, src source.Source) trss.Item {
 
 	// Build engagement map from metadata if available.
 	engagement := map[string]any{}
-	if item.Metadata != nil {
-		if v, ok := item.Metadata["points"]; ok {
-			engagement["points"] = v
-		}
-		if v, ok := item.Metadata["comments"]; ok {
-			engagement["comments"] = v
-		}
-		if v, ok := item.Metadata["stars"]; ok {
-			engagement["stars"] = v
+	for _, key := range engagementKeys {
+		if v, ok := item.Metadata[key]; ok {
+			engagement[key] = v
 		}
 	}
 
@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/extract"
+	"github.com/jcornudella/hotbrew/internal/store"
+	"github.com/jcornudella/hotbrew/pkg/source"
+	"github.com/jcornudella/hotbrew/pkg/trss"
+)
+
+const (
+	extractWorkers = 4                // article downloads in flight per source
+	extractTimeout = 15 * time.Second // deadline for each article download
+	enrichTimeout  = 2 * time.Minute  // deadline for all of a source's downloads
+	maxArticle     = 20000            // bytes of article text kept in Body
+
+	// fullBodyWords is the length from which a body the source supplied
+	// is taken to be the whole article, so the page isn't fetched.
+	fullBodyWords = 150
+)
+
+// CommonSettings are the settings sync reads for every source, whatever
+// its driver.
+var CommonSettings = []source.Field{
+	{Name: "extract", Type: "bool", Setting: true, Help: "replace item bodies with the linked article's text (default false)"},
+	{Name: "extract_denylist", Type: "strings", Setting: true, Help: "extra domains never fetched for extraction"},
+}
+
+// convertSection converts a fetched section and, when the source opted in
+// with the "extract" setting, enriches the items that aren't stored yet.
+// Enrichment stops at enrichTimeout; items it hasn't reached keep their
+// bodies. mu guards st.
+func convertSection(ctx context.Context, st *store.Store, mu sync.Locker, section *source.Section, src source.Source, cfg source.Config, opts Options) []trss.Item {
+	items := ConvertSection(section, src)
+	if len(items) == 0 || !cfg.Bool("extract", false) {
+		return items
+	}
+
+	var fresh []*trss.Item
+	mu.Lock()
+	for i := range items {
+		if !st.HasItem(items[i].ID) {
+			fresh = append(fresh, &items[i])
+		}
+	}
+	mu.Unlock()
+
+	denylist := append(append([]string{}, extract.DefaultDenylist...), opts.ExtractDenylist...)
+	denylist = append(denylist, cfg.Strings("extract_denylist")...)
+	ctx, cancel := context.WithTimeout(ctx, enrichTimeout)
+	defer cancel()
+	EnrichItems(ctx, fresh, denylist)
+	return items
+}
+
+// EnrichItems replaces each item's Body with the main text of the article
+// it links to and records "word_count" and "reading_minutes" in its Meta.
+// Items whose URL is on denylist keep their body, as do items whose body
+// already looks like the full article; those still get a word count.
+// Extraction is best effort: pages that fail to download or parse are
+// left as they were.
+func EnrichItems(ctx context.Context, items []*trss.Item, denylist []string) {
+	sem := make(chan struct{}, extractWorkers)
+	var wg sync.WaitGroup
+	for _, item := range items {
+		if words := len(strings.Fields(item.Body)); words >= fullBodyWords {
+			setReadingMeta(item, extract.Article{Text: item.Body, Words: words})
+			continue
+		}
+		if item.URL == "" || extract.Denied(item.URL, denylist) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(item *trss.Item) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			fetchCtx, cancel := context.WithTimeout(ctx, extractTimeout)
+			defer cancel()
+			article, err := extract.Fetch(fetchCtx, item.URL)
+			if err != nil || article.Words < len(strings.Fields(item.Body)) {
+				return
+			}
+			item.Body = article.Text
+			if len(item.Body) > maxArticle {
+				item.Body = strings.ToValidUTF8(item.Body[:maxArticle], "") + "…"
+			}
+			setReadingMeta(item, article)
+		}(item)
+	}
+	wg.Wait()
+}
+
+func setReadingMeta(item *trss.Item, article extract.Article) {
+	if item.Meta == nil {
+		item.Meta = map[string]any{}
+	}
+	item.Meta["word_count"] = article.Words
+	item.Meta["reading_minutes"] = article.ReadingMinutes()
+}
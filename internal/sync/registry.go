@@ -0,0 +1,97 @@
+package sync
+
+import (
+	"github.com/jcornudella/hotbrew/internal/config"
+	"github.com/jcornudella/hotbrew/internal/store"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+	"github.com/jcornudella/hotbrew/pkg/source"
+
+	// Register the built-in drivers with source.FromSpec.
+	_ "github.com/jcornudella/hotbrew/internal/sources/all"
+)
+
+// BuildRegistry registers the sources declared in the active profile plus the
+// enabled feeds stored in the sources table (e.g. from `hotbrew add`).
+// Entries whose driver is unknown or whose fields are invalid are skipped.
+// st may be nil, in which case only profile sources are registered.
+func BuildRegistry(cfg *config.Config, st *store.Store) *source.Registry {
+	registry := source.NewRegistry()
+	prof := profile.Load(cfg.GetProfileName())
+	profileFeeds := make(map[string]bool)
+	for _, spec := range prof.Sources {
+		srcCfg, ok := SpecConfig(cfg, spec)
+		if !ok {
+			continue
+		}
+
+		// Build from the merged settings so drivers that read them at
+		// construction (exec's ttl, say) see the config entry's too.
+		spec.Settings = srcCfg.Settings
+		src, err := source.FromSpec(spec)
+		if err != nil {
+			continue
+		}
+		registry.RegisterWithConfig(spec.Key, src, srcCfg)
+		if spec.FeedURL != "" {
+			profileFeeds[spec.FeedURL] = true
+		}
+	}
+
+	if st == nil {
+		return registry
+	}
+	records, err := st.ListSources()
+	if err != nil {
+		return registry
+	}
+	for _, rec := range records {
+		// Rows without a URL are bookkeeping entries created by sync for
+		// profile sources; only subscriptions carry their own feed URL.
+		if !rec.Enabled || rec.URL == "" || profileFeeds[rec.URL] {
+			continue
+		}
+		spec := profile.SourceSpec{
+			Key:      StoreKey(rec.Kind, rec.ID),
+			Driver:   rec.Kind,
+			Name:     rec.Name,
+			Icon:     rec.Icon,
+			FeedURL:  rec.URL,
+			Tags:     source.Config{Settings: rec.Settings}.Strings("tags"),
+			Settings: rec.Settings,
+		}
+		src, err := source.FromSpec(spec)
+		if err != nil {
+			continue
+		}
+		registry.RegisterWithConfig(spec.Key, src, source.Config{
+			Enabled:  true,
+			Settings: rec.Settings,
+		})
+	}
+	return registry
+}
+
+// SpecConfig returns the fetch config for a profile entry: the settings of
+// the config entry named by its ConfigKey, overridden by its own. ok is
+// false when that config entry is missing or disabled.
+func SpecConfig(cfg *config.Config, spec profile.SourceSpec) (srcCfg source.Config, ok bool) {
+	srcCfg = source.Config{Enabled: true}
+	if spec.ConfigKey != "" {
+		sc, ok := cfg.Sources[spec.ConfigKey]
+		if !ok || !sc.Enabled {
+			return srcCfg, false
+		}
+		srcCfg.Settings = sc.Settings
+	}
+	if len(spec.Settings) > 0 {
+		merged := make(map[string]any, len(srcCfg.Settings)+len(spec.Settings))
+		for k, v := range srcCfg.Settings {
+			merged[k] = v
+		}
+		for k, v := range spec.Settings {
+			merged[k] = v
+		}
+		srcCfg.Settings = merged
+	}
+	return srcCfg, true
+}
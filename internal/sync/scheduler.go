@@ -0,0 +1,216 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jcornudella/hotbrew/internal/store"
+	"github.com/jcornudella/hotbrew/pkg/source"
+	"github.com/jcornudella/hotbrew/pkg/trss"
+)
+
+// Options controls how SyncAll schedules source fetches.
+type Options struct {
+	Workers int           // max sources fetched concurrently
+	Timeout time.Duration // deadline for each individual source
+	Force   bool          // fetch sources even if their TTL or breaker says to wait
+
+	// After BreakerThreshold consecutive failures a source's circuit
+	// breaker opens and the source is skipped for BreakerCooldown.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	// ExtractDenylist adds domains to extract.DefaultDenylist for sources
+	// that opt into full-text extraction.
+	ExtractDenylist []string
+}
+
+// DefaultOptions returns the scheduler defaults.
+func DefaultOptions() Options {
+	return Options{
+		Workers:          4,
+		Timeout:          30 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Hour,
+	}
+}
+
+func (o Options) withDefaults() Options {
+	def := DefaultOptions()
+	if o.Workers <= 0 {
+		o.Workers = def.Workers
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = def.Timeout
+	}
+	if o.BreakerThreshold <= 0 {
+		o.BreakerThreshold = def.BreakerThreshold
+	}
+	if o.BreakerCooldown <= 0 {
+		o.BreakerCooldown = def.BreakerCooldown
+	}
+	return o
+}
+
+// scheduler fetches sources in parallel. Fetches run concurrently, but
+// store access is serialized because SQLite allows a single writer.
+type scheduler struct {
+	st    *store.Store
+	opts  Options
+	runID int // sync_runs row, 0 if the run could not be recorded
+	mu    sync.Mutex
+}
+
+// SyncAll fetches all sources in the registry and stores them.
+// Sources are fetched in parallel, each under its own timeout, and sources
+// synced more recently than their TTL are skipped unless opts.Force is set.
+// The run and each source's outcome are recorded in the sync history.
+// Results are returned in key order.
+func SyncAll(ctx context.Context, st *store.Store, registry *source.Registry, opts Options) []Result {
+	s := &scheduler{st: st, opts: opts.withDefaults()}
+	runID, err := st.StartSyncRun(time.Now())
+	if err != nil {
+		log.Printf("sync: record run: %v", err)
+	}
+	s.runID = runID
+
+	sources := registry.All()
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]Result, len(names))
+	sem := make(chan struct{}, s.opts.Workers)
+	var wg sync.WaitGroup
+
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = Result{SourceName: name, Err: ctx.Err()}
+				return
+			}
+			results[i] = s.run(ctx, name, sources[name], registry.Config(name))
+		}(i, name)
+	}
+
+	wg.Wait()
+	if runID > 0 {
+		st.FinishSyncRun(runID, time.Now())
+	}
+	return results
+}
+
+// run syncs a single source unless it is still fresh or its breaker is open.
+func (s *scheduler) run(ctx context.Context, name string, src source.Source, cfg source.Config) Result {
+	start := time.Now()
+	if !s.opts.Force {
+		if reason := s.skipReason(name, src); reason != "" {
+			res := Result{SourceName: name, SkipReason: reason, StartedAt: start}
+			s.record(res)
+			return res
+		}
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	fetchCtx, trace := source.WithTrace(fetchCtx)
+	section, err := src.Fetch(fetchCtx, cfg)
+	cancel()
+
+	// Article extraction runs outside the fetch timeout, under a deadline
+	// of its own.
+	var items []trss.Item
+	if err == nil {
+		items = convertSection(ctx, s.st, &s.mu, section, src, cfg, s.opts)
+	}
+
+	s.mu.Lock()
+	res := storeSection(s.st, name, src, items, err, s.opts)
+	if res.stored() {
+		trace.Commit(s.st)
+	}
+	s.mu.Unlock()
+
+	if res.stored() {
+		afterCtx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+		if err := trace.RunAfterStore(afterCtx); err != nil {
+			log.Printf("sync: %s: %v", name, err)
+		}
+		cancel()
+	}
+
+	res.StartedAt = start
+	res.Duration = time.Since(start)
+	res.HTTPStatus = trace.Status()
+	s.record(res)
+	return res
+}
+
+// record appends a source's outcome to the current run's history.
+func (s *scheduler) record(res Result) {
+	if s.runID == 0 {
+		return
+	}
+	rec := store.SyncResultRecord{
+		RunID:      s.runID,
+		SourceKey:  res.SourceName,
+		Status:     res.Status(),
+		StartedAt:  res.StartedAt,
+		FinishedAt: res.StartedAt.Add(res.Duration),
+		Inserted:   res.ItemCount,
+		Duplicates: res.Duplicates,
+		Fetched:    res.ItemCount + res.Duplicates,
+		HTTPStatus: res.HTTPStatus,
+	}
+	if res.Err != nil {
+		rec.Error = res.Err.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.st.InsertSyncResult(rec); err != nil {
+		log.Printf("sync: record result for %s: %v", res.SourceName, err)
+	}
+}
+
+// skipReason explains why a source should not be fetched right now: its
+// circuit breaker is open, or it was synced within its TTL.
+func (s *scheduler) skipReason(name string, src source.Source) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := resolveSourceID(s.st, name, src)
+	if err != nil {
+		return ""
+	}
+	rec, err := s.st.GetSource(id)
+	if err != nil {
+		return ""
+	}
+
+	if rec.BreakerUntil != nil && time.Now().Before(*rec.BreakerUntil) {
+		return fmt.Sprintf("circuit open after %d failures, retry after %s",
+			rec.SyncErrors, rec.BreakerUntil.Local().Format("15:04"))
+	}
+
+	ttl := src.TTL()
+	if ttl <= 0 || rec.LastSync == nil || rec.LastSync.IsZero() {
+		return ""
+	}
+	age := time.Since(*rec.LastSync)
+	if age >= ttl {
+		return ""
+	}
+	return fmt.Sprintf("synced %s ago, next in %s",
+		age.Round(time.Second), (ttl - age).Round(time.Second))
+}
@@ -2,31 +2,52 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	gosync "sync"
+	"time"
 
 	"github.com/jcornudella/hotbrew/internal/store"
 	"github.com/jcornudella/hotbrew/pkg/source"
+	"github.com/jcornudella/hotbrew/pkg/trss"
 )
 
 // Result holds the outcome of a sync operation.
 type Result struct {
-	SourceName string
-	ItemCount  int
-	Err        error
+	SourceName  string
+	ItemCount   int // new items stored
+	Duplicates  int // fetched items that were already in the store
+	Err         error
+	StartedAt   time.Time
+	Duration    time.Duration // time spent fetching and storing
+	SkipReason  string        // set when the source was not fetched
+	NotModified bool          // the server reported no changes since the last fetch
+	HTTPStatus  int           // status of the last HTTP response, if any
+
+	insertErrors int // items that failed to insert
 }
 
-// SyncAll fetches all sources in the registry and stores them.
-func SyncAll(ctx context.Context, st *store.Store, registry *source.Registry) []Result {
-	var results []Result
+// stored reports whether every fetched item made it into the store, so the
+// fetch's cache validators may be saved.
+func (r Result) stored() bool {
+	return r.Err == nil && r.insertErrors == 0
+}
 
-	for name, src := range registry.All() {
-		cfg := source.Config{Enabled: true}
-		res := syncSource(ctx, st, name, src, cfg)
-		results = append(results, res)
+// Status classifies the result as recorded in the sync history.
+func (r Result) Status() string {
+	switch {
+	case r.Err != nil:
+		return "error"
+	case r.SkipReason != "":
+		return "skipped"
+	case r.NotModified:
+		return "not_modified"
+	default:
+		return "ok"
 	}
-
-	return results
 }
 
 // SyncSource fetches a single source and stores its items.
@@ -34,60 +55,127 @@ func SyncSource(ctx context.Context, st *store.Store, name string, src source.So
 	return syncSource(ctx, st, name, src, cfg)
 }
 
+// StoreKey returns the registry key for a source that is backed by a row in
+// the sources table, such as a feed added with `hotbrew add`.
+func StoreKey(kind string, id int) string {
+	return fmt.Sprintf("%s#%d", kind, id)
+}
+
+// resolveSourceID maps a registry key to its row in the sources table.
+// Store-backed keys carry their row ID, and are only taken as such when
+// they are exactly the StoreKey of a subscription row, so a profile key
+// that merely looks like one ("blog#2") keeps its own row. Profile sources
+// are looked up by name and key, and created on first sync.
+func resolveSourceID(st *store.Store, name string, src source.Source) (int, error) {
+	if kind, rest, ok := strings.Cut(name, "#"); ok {
+		if id, err := strconv.Atoi(rest); err == nil && StoreKey(kind, id) == name {
+			if rec, err := st.GetSource(id); err == nil && rec.Kind == kind && rec.URL != "" {
+				return id, nil
+			}
+		}
+	}
+	return st.GetOrCreateSource(src.Name(), name, "", src.Icon())
+}
+
 func syncSource(ctx context.Context, st *store.Store, name string, src source.Source, cfg source.Config) Result {
+	start := time.Now()
+	ctx, trace := source.WithTrace(ctx)
 	section, err := src.Fetch(ctx, cfg)
-	if err != nil {
+	opts := DefaultOptions()
+	var items []trss.Item
+	if err == nil {
+		items = convertSection(ctx, st, new(gosync.Mutex), section, src, cfg, opts)
+	}
+	res := storeSection(st, name, src, items, err, opts)
+	if res.stored() {
+		trace.Commit(st)
+		if err := trace.RunAfterStore(ctx); err != nil {
+			log.Printf("sync: %s: %v", name, err)
+		}
+	}
+	res.StartedAt = start
+	res.Duration = time.Since(start)
+	res.HTTPStatus = trace.Status()
+	return res
+}
+
+// storeSection records the outcome of a fetch: the converted items are
+// inserted and the source's sync timestamp is updated, or its error counter
+// is bumped and, past the breaker threshold, its circuit breaker is opened.
+func storeSection(st *store.Store, name string, src source.Source, items []trss.Item, fetchErr error, opts Options) Result {
+	// A 304 from a conditional request is a successful sync with no new items.
+	notModified := errors.Is(fetchErr, source.ErrNotModified)
+	if notModified {
+		fetchErr = nil
+		items = nil
+	}
+
+	if fetchErr != nil {
 		// Track the error in the store if we have a source ID.
-		sourceID, _ := st.GetOrCreateSource(src.Name(), name, "", src.Icon())
+		sourceID, _ := resolveSourceID(st, name, src)
 		if sourceID > 0 {
 			st.IncrSyncErrors(sourceID)
+			if rec, err := st.GetSource(sourceID); err == nil && rec.SyncErrors >= opts.BreakerThreshold {
+				st.OpenBreaker(sourceID, time.Now().Add(opts.BreakerCooldown))
+			}
 		}
-		return Result{SourceName: name, Err: fmt.Errorf("fetch %s: %w", name, err)}
-	}
-
-	if section == nil || len(section.Items) == 0 {
-		return Result{SourceName: name, ItemCount: 0}
+		return Result{SourceName: name, Err: fmt.Errorf("fetch %s: %w", name, fetchErr)}
 	}
 
 	// Ensure the source exists in the store.
-	sourceID, err := st.GetOrCreateSource(src.Name(), name, "", src.Icon())
+	sourceID, err := resolveSourceID(st, name, src)
 	if err != nil {
 		return Result{SourceName: name, Err: fmt.Errorf("register source %s: %w", name, err)}
 	}
 
-	// Convert and insert items.
-	items := ConvertSection(section, src)
-	inserted := 0
+	// Insert items.
+	inserted, duplicates, failed := 0, 0, 0
 	for _, item := range items {
-		if err := st.InsertItem(item, sourceID); err != nil {
+		isNew, err := st.InsertItemNew(item, sourceID)
+		if err != nil {
 			log.Printf("sync: insert item %s: %v", item.ID, err)
+			failed++
 			continue
 		}
-		inserted++
+		if isNew {
+			inserted++
+		} else {
+			duplicates++
+		}
 	}
 
-	// Update sync timestamp.
+	// Update sync timestamp, even for empty sections, so TTLs are honored.
 	st.UpdateLastSync(sourceID)
 
-	return Result{SourceName: name, ItemCount: inserted}
+	return Result{SourceName: name, ItemCount: inserted, Duplicates: duplicates, NotModified: notModified, insertErrors: failed}
 }
 
 // PrintResults logs sync results to stdout.
 func PrintResults(results []Result) {
 	total := 0
 	errs := 0
+	skipped := 0
 	for _, r := range results {
-		if r.Err != nil {
+		switch {
+		case r.Err != nil:
 			fmt.Printf("  ✗ %s: %v\n", r.SourceName, r.Err)
 			errs++
-		} else {
-			fmt.Printf("  ✓ %s: %d items\n", r.SourceName, r.ItemCount)
+		case r.SkipReason != "":
+			fmt.Printf("  · %s: skipped, %s\n", r.SourceName, r.SkipReason)
+			skipped++
+		case r.NotModified:
+			fmt.Printf("  ✓ %s: not modified (%s)\n", r.SourceName, r.Duration.Round(10*time.Millisecond))
+		default:
+			fmt.Printf("  ✓ %s: %d new, %d seen (%s)\n", r.SourceName, r.ItemCount, r.Duplicates, r.Duration.Round(10*time.Millisecond))
 			total += r.ItemCount
 		}
 	}
-	fmt.Printf("\nSynced %d items from %d sources", total, len(results)-errs)
+	fmt.Printf("\nSynced %d new items from %d sources", total, len(results)-errs-skipped)
 	if errs > 0 {
 		fmt.Printf(" (%d errors)", errs)
 	}
+	if skipped > 0 {
+		fmt.Printf(" (%d skipped)", skipped)
+	}
 	fmt.Println()
 }
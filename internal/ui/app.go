@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
+	gosync "sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -15,10 +17,8 @@ import (
 	"github.com/jcornudella/hotbrew/internal/config"
 	"github.com/jcornudella/hotbrew/internal/curation"
 	"github.com/jcornudella/hotbrew/internal/sinks"
-	"github.com/jcornudella/hotbrew/internal/sources/github"
-	"github.com/jcornudella/hotbrew/internal/sources/hackernews"
-	"github.com/jcornudella/hotbrew/internal/sources/hnsearch"
 	"github.com/jcornudella/hotbrew/internal/store"
+	hsync "github.com/jcornudella/hotbrew/internal/sync"
 	"github.com/jcornudella/hotbrew/internal/ui/components"
 	"github.com/jcornudella/hotbrew/internal/ui/theme"
 	"github.com/jcornudella/hotbrew/pkg/profile"
@@ -120,7 +120,7 @@ func NewModel(cfg *config.Config, st ...*store.Store) Model {
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	loadCmd := fetchSections(m.cfg)
+	loadCmd := fetchSections(m.cfg, m.store)
 	if m.store != nil {
 		loadCmd = loadFromStore(m.store, m.cfg)
 	}
@@ -138,12 +138,12 @@ func loadFromStore(st *store.Store, cfg *config.Config) tea.Cmd {
 		digest, err := engine.GenerateDigest(cfg.GetDigestWindow(), cfg.GetDigestMax(), "Hotbrew Digest")
 		if err != nil || digest == nil || len(digest.Items) == 0 {
 			// Fall back to live fetch if store is empty.
-			return fetchSections(cfg)()
+			return fetchSections(cfg, st)()
 		}
 
 		sections := sinks.DigestToSections(digest)
 		if len(sections) == 0 {
-			return fetchSections(cfg)()
+			return fetchSections(cfg, st)()
 		}
 
 		return sectionsLoadedMsg{sections: sections}
@@ -157,53 +157,45 @@ func animTick() tea.Cmd {
 	})
 }
 
-// fetchSections fetches data from all enabled sources
-func fetchSections(cfg *config.Config) tea.Cmd {
+// fetchSections fetches every source in the active profile, plus the
+// feeds subscribed in st if it isn't nil, directly, for when the store has
+// nothing to show. Like sync, it fetches at most sync_workers sources at a
+// time, each under the sync_timeout deadline.
+func fetchSections(cfg *config.Config, st *store.Store) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		var sections []*source.Section
-
-		// Fetch Hacker News if enabled
-		if src, ok := cfg.Sources["hackernews"]; ok && src.Enabled {
-			hn := hackernews.New()
-			section, err := hn.Fetch(ctx, source.Config{
-				Enabled:  src.Enabled,
-				Settings: src.Settings,
-			})
-			if err == nil && section != nil {
-				sections = append(sections, section)
-			}
-		}
-
-		// Claude Code & Vibe Coding search
-		vibeSearch := hnsearch.New(
-			"Claude Code & Vibe Coding",
-			[]string{"Claude Code", "vibe coding", "AI coding assistant", "Anthropic Claude"},
-			"🤖",
+		registry := hsync.BuildRegistry(cfg, st)
+		var (
+			mu       gosync.Mutex
+			wg       gosync.WaitGroup
+			sections []*source.Section
 		)
-		vibeSection, vibeErr := vibeSearch.Fetch(ctx, source.Config{
-			Enabled:  true,
-			Settings: map[string]any{"max": 8},
-		})
-		if vibeErr == nil && vibeSection != nil && len(vibeSection.Items) > 0 {
-			sections = append(sections, vibeSection)
+		sem := make(chan struct{}, cfg.GetSyncWorkers())
+		for name, src := range registry.All() {
+			wg.Add(1)
+			go func(src source.Source, srcCfg source.Config) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				ctx, cancel := context.WithTimeout(context.Background(), cfg.GetSyncTimeout())
+				defer cancel()
+				section, err := src.Fetch(ctx, srcCfg)
+				if err != nil || section == nil || len(section.Items) == 0 {
+					return
+				}
+				mu.Lock()
+				sections = append(sections, section)
+				mu.Unlock()
+			}(src, registry.Config(name))
 		}
+		wg.Wait()
 
-		// GitHub trending AI/coding repos
-		ghTrending := github.New(
-			"GitHub Trending",
-			[]string{"ai", "llm", "machine-learning", "gpt", "claude"},
-			"🐙",
-		)
-		ghSection, ghErr := ghTrending.Fetch(ctx, source.Config{
-			Enabled:  true,
-			Settings: map[string]any{"max": 6},
+		sort.SliceStable(sections, func(i, j int) bool {
+			if sections[i].Priority != sections[j].Priority {
+				return sections[i].Priority < sections[j].Priority
+			}
+			return sections[i].Name < sections[j].Name
 		})
-		if ghErr == nil && ghSection != nil && len(ghSection.Items) > 0 {
-			sections = append(sections, ghSection)
-		}
 
 		return sectionsLoadedMsg{sections: sections}
 	}
@@ -333,7 +325,7 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.store != nil {
 			return m, loadFromStore(m.store, m.cfg)
 		}
-		return m, fetchSections(m.cfg)
+		return m, fetchSections(m.cfg, m.store)
 
 	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
 		// Quick jump to section
@@ -561,7 +553,7 @@ func (m Model) applyProfile(name string) (tea.Model, tea.Cmd) {
 	if m.store != nil {
 		return m, loadFromStore(m.store, m.cfg)
 	}
-	return m, fetchSections(m.cfg)
+	return m, fetchSections(m.cfg, m.store)
 }
 
 func (m Model) saveProfileEditor() (tea.Model, tea.Cmd) {
@@ -59,6 +59,9 @@ func Item(item source.Item, t theme.Theme, width int, selected bool, isFirst boo
 	}
 
 	badge := priorityBadge(item.Priority, t)
+	if secs := intFromAny(item.Metadata["duration"]); secs > 0 {
+		badge += " " + t.MutedStyle().Render(source.FormatDuration(secs))
+	}
 	badgeWidth := 0
 	if badge != "" {
 		badgeWidth = lipgloss.Width(badge) + 1
@@ -142,6 +145,13 @@ func ItemExpanded(item source.Item, t theme.Theme, width int, selected bool, isF
 	}
 
 	meta := buildMetadataLine(item)
+	if mins := intFromAny(item.Metadata["reading_minutes"]); mins > 0 {
+		reading := fmt.Sprintf("📖 %d min read", mins)
+		if meta != "" {
+			reading = meta + "  •  " + reading
+		}
+		meta = reading
+	}
 	if meta != "" {
 		cardSections = append(cardSections, t.MutedStyle().Render(meta))
 	}
@@ -294,7 +304,11 @@ func buildMetadataLine(item source.Item) string {
 	category := strings.ToLower(item.Category)
 	switch category {
 	case "hackernews":
-		score := intFromAny(meta["score"])
+		score := intFromAny(meta["points"])
+		if score == 0 {
+			// Items stored before the driver emitted "points".
+			score = intFromAny(meta["score"])
+		}
 		comments := intFromAny(meta["comments"])
 		if score == 0 && comments == 0 {
 			return ""
@@ -334,6 +348,44 @@ func buildMetadataLine(item source.Item) string {
 			parts = append(parts, sanitize.Text(language))
 		}
 		return sanitize.Text(strings.Join(parts, "  •  "))
+	case "pr", "issue", "notification":
+		reasons := stringSlice(meta["reasons"])
+		if len(reasons) == 0 {
+			return ""
+		}
+		return sanitize.Text(strings.ReplaceAll(strings.Join(reasons, ", "), "_", " "))
+	case "video":
+		var parts []string
+		if secs := intFromAny(meta["duration"]); secs > 0 {
+			parts = append(parts, "⏱ "+source.FormatDuration(secs))
+		}
+		if channel, _ := meta["channel"].(string); channel != "" {
+			parts = append(parts, sanitize.Text(channel))
+		}
+		return sanitize.Text(strings.Join(parts, "  •  "))
+	case "podcast":
+		var parts []string
+		if secs := intFromAny(meta["duration"]); secs > 0 {
+			parts = append(parts, "⏱ "+source.FormatDuration(secs))
+		}
+		if ep := intFromAny(meta["episode"]); ep > 0 {
+			parts = append(parts, fmt.Sprintf("episode %d", ep))
+		}
+		if kind, _ := meta["enclosure_type"].(string); kind != "" {
+			parts = append(parts, sanitize.Text(kind))
+		}
+		return sanitize.Text(strings.Join(parts, "  •  "))
+	case "question":
+		parts := []string{fmt.Sprintf("▲ %d", intFromAny(meta["points"]))}
+		answers := fmt.Sprintf("%d answers", intFromAny(meta["answers"]))
+		if accepted, _ := meta["accepted"].(bool); accepted {
+			answers += " ✓"
+		}
+		parts = append(parts, answers)
+		if site, _ := meta["site"].(string); site != "" {
+			parts = append(parts, sanitize.Text(site))
+		}
+		return sanitize.Text(strings.Join(parts, "  •  "))
 	case "lobsters":
 		score := intFromAny(meta["score"])
 		comments := intFromAny(meta["comments"])
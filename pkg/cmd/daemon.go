@@ -5,6 +5,7 @@ import (
 
 	"github.com/jcornudella/hotbrew/internal/config"
 	"github.com/jcornudella/hotbrew/internal/daemon"
+	hsync "github.com/jcornudella/hotbrew/internal/sync"
 )
 
 func (r *Root) cmdDaemon(args []string) error {
@@ -19,8 +20,7 @@ func (r *Root) cmdDaemon(args []string) error {
 		if err != nil {
 			return fmt.Errorf("load config: %w", err)
 		}
-		registry := buildRegistry(cfg)
-		if err := daemon.Start(cfg, registry); err != nil {
+		if err := daemon.Start(cfg, hsync.BuildRegistry); err != nil {
 			return err
 		}
 	case "stop":
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jcornudella/hotbrew/internal/cli"
+	"github.com/jcornudella/hotbrew/internal/config"
+	"github.com/jcornudella/hotbrew/internal/store"
+)
+
+func (r *Root) cmdDrivers(args []string) error {
+	if len(args) > 0 && args[0] == "test" {
+		if len(args) < 2 {
+			return errors.New("usage: hotbrew drivers test <key>")
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		return withStore(func(st *store.Store) error {
+			return cli.DriverTest(cfg, st, args[1])
+		})
+	}
+	cli.Drivers()
+	return nil
+}
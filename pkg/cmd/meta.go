@@ -14,13 +14,20 @@ func (r *Root) cmdHelp(args []string) error {
 USAGE:
     hotbrew                  Launch TUI digest viewer
     hotbrew sync             Fetch all sources → SQLite
+    hotbrew sync --force     Fetch even sources still within their TTL
+    hotbrew sync --history   Show recent syncs and failing sources
     hotbrew digest           Show curated digest (pretty)
     hotbrew digest --json    Output as TRSS NDJSON
     hotbrew list [flags]     List items from store
     hotbrew open <id>        Open item in browser, mark read
     hotbrew save <id>        Save an item for later
-    hotbrew add <url> [name] Add an RSS feed source
+    hotbrew add <url> [name] Add an RSS, Atom or JSON feed
     hotbrew sources          List registered sources
+    hotbrew sources check    Dry-run fetch of profile sources; exits 1 on failure
+    hotbrew drivers          List source drivers and their fields
+    hotbrew drivers test <k> Fetch one source and print its items (dry run)
+    hotbrew import opml <f>  Subscribe to every feed in an OPML file
+    hotbrew export opml [f]  Write feeds as OPML (stdout by default)
     hotbrew curate <url>     Manually save a link (auto-fetches title)
     hotbrew mute <domain>    Mute a domain
     hotbrew boost <tag>      Boost items with a tag
@@ -35,6 +42,15 @@ USAGE:
     hotbrew serve [addr]     Run the web server
     hotbrew help             Show this help
 
+SYNC FLAGS:
+    --force             Ignore source TTLs and open circuit breakers
+    --workers <n>       Fetch up to N sources in parallel (default 4)
+    --timeout <dur>     Per-source fetch timeout (default 30s)
+
+IMPORT FLAGS:
+    --profile <name>    Add feeds to a profile instead of the store
+    --by-category       Add feeds to one profile per OPML folder
+
 LIST FLAGS:
     --unread            Only show unread items
     --source <name>     Filter by source name
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jcornudella/hotbrew/internal/cli"
+	"github.com/jcornudella/hotbrew/internal/config"
+	"github.com/jcornudella/hotbrew/internal/store"
+	"github.com/jcornudella/hotbrew/pkg/profile"
+)
+
+func (r *Root) cmdImport(args []string) error {
+	if len(args) == 0 || args[0] != "opml" {
+		fmt.Println("Usage: hotbrew import opml <file> [--profile <name> | --by-category]")
+		return nil
+	}
+
+	opts := cli.ImportOptions{}
+	rest := args[1:]
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case "--profile":
+			if i+1 < len(rest) {
+				i++
+				opts.Profile = rest[i]
+			}
+		case "--by-category":
+			opts.ByCategory = true
+		default:
+			opts.Path = rest[i]
+		}
+	}
+
+	return withStore(func(st *store.Store) error {
+		cli.ImportOPML(st, opts)
+		return nil
+	})
+}
+
+func (r *Root) cmdExport(args []string) error {
+	if len(args) == 0 || args[0] != "opml" {
+		fmt.Println("Usage: hotbrew export opml [file]")
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	st, err := store.Open(cfg.GetDBPath())
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer st.Close()
+
+	out := os.Stdout
+	if len(args) > 1 {
+		f, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	cli.ExportOPML(st, profile.Load(cfg.GetProfileName()), out)
+	if out != os.Stdout {
+		fmt.Printf("✓ Exported subscriptions to %s\n", args[1])
+	}
+	return nil
+}
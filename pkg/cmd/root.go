@@ -34,6 +34,8 @@ func New(version string) *Root {
 	r.register(&command{name: "sync", run: r.cmdSync})
 	r.register(&command{name: "digest", run: r.cmdDigest})
 	r.register(&command{name: "add", run: r.cmdAdd})
+	r.register(&command{name: "import", run: r.cmdImport})
+	r.register(&command{name: "export", run: r.cmdExport})
 	r.register(&command{name: "list", aliases: []string{"ls"}, run: r.cmdList})
 	r.register(&command{name: "open", run: r.cmdOpen})
 	r.register(&command{name: "save", run: r.cmdSave})
@@ -41,6 +43,7 @@ func New(version string) *Root {
 	r.register(&command{name: "boost", run: r.cmdBoost})
 	r.register(&command{name: "rules", run: r.cmdRules})
 	r.register(&command{name: "sources", run: r.cmdSources})
+	r.register(&command{name: "drivers", run: r.cmdDrivers})
 	r.register(&command{name: "curate", run: r.cmdCurate})
 	r.register(&command{name: "stream", run: r.cmdStream})
 	r.register(&command{name: "daemon", run: r.cmdDaemon})
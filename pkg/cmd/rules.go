@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/jcornudella/hotbrew/internal/cli"
+	"github.com/jcornudella/hotbrew/internal/config"
 	"github.com/jcornudella/hotbrew/internal/store"
 )
 
@@ -39,6 +42,17 @@ func (r *Root) cmdRules(args []string) error {
 }
 
 func (r *Root) cmdSources(args []string) error {
+	if len(args) > 0 && args[0] == "check" {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		key := ""
+		if len(args) > 1 {
+			key = args[1]
+		}
+		return cli.SourcesCheck(cfg, key)
+	}
 	return withStore(func(st *store.Store) error {
 		cli.Sources(st)
 		return nil
@@ -7,46 +7,107 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jcornudella/hotbrew/internal/cli"
 	"github.com/jcornudella/hotbrew/internal/config"
-	"github.com/jcornudella/hotbrew/internal/sources/arxiv"
-	"github.com/jcornudella/hotbrew/internal/sources/github"
-	"github.com/jcornudella/hotbrew/internal/sources/hackernews"
-	"github.com/jcornudella/hotbrew/internal/sources/hnsearch"
-	"github.com/jcornudella/hotbrew/internal/sources/lobsters"
-	"github.com/jcornudella/hotbrew/internal/sources/reddit"
-	"github.com/jcornudella/hotbrew/internal/sources/tldr"
 	"github.com/jcornudella/hotbrew/internal/store"
 	hsync "github.com/jcornudella/hotbrew/internal/sync"
-	"github.com/jcornudella/hotbrew/pkg/profile"
 	"github.com/jcornudella/hotbrew/pkg/source"
 )
 
 func (r *Root) cmdSync(args []string) error {
-	if len(args) > 0 && args[0] == "--remote" {
+	var (
+		remote, force    bool
+		history, workers int
+		timeout          time.Duration
+	)
+	for i := 0; i < len(args); i++ {
+		flag := args[i]
+		switch flag {
+		case "--remote":
+			remote = true
+		case "--force":
+			force = true
+		case "--history":
+			history = 10
+			// The number of runs is optional.
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				i++
+				n, err := strconv.Atoi(args[i])
+				if err != nil || n < 1 {
+					return fmt.Errorf("invalid --history %q: want a positive number of runs", args[i])
+				}
+				history = n
+			}
+		case "--workers":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", flag)
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid --workers %q: want a positive number", args[i])
+			}
+			workers = n
+		case "--timeout":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", flag)
+			}
+			i++
+			d, err := time.ParseDuration(args[i])
+			if err != nil {
+				return fmt.Errorf("invalid --timeout %q: %w", args[i], err)
+			}
+			if d <= 0 {
+				return fmt.Errorf("invalid --timeout %q: want a positive duration", args[i])
+			}
+			timeout = d
+		}
+	}
+
+	if remote {
 		return r.cmdSyncRemote()
 	}
+	if history > 0 {
+		return withStore(func(st *store.Store) error {
+			cli.SyncHistory(st, history)
+			return nil
+		})
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("load config: %w", err)
 	}
 
+	opts := hsync.Options{
+		Workers:         cfg.GetSyncWorkers(),
+		Timeout:         cfg.GetSyncTimeout(),
+		Force:           force,
+		ExtractDenylist: cfg.ExtractDenylist,
+	}
+	if workers > 0 {
+		opts.Workers = workers
+	}
+	if timeout > 0 {
+		opts.Timeout = timeout
+	}
+
 	st, err := store.Open(cfg.GetDBPath())
 	if err != nil {
 		return fmt.Errorf("open store: %w", err)
 	}
 	defer st.Close()
+	source.DefaultClient.SetValidatorStore(st)
+	source.DefaultClient.SetTokenStore(st)
 
-	registry := buildRegistry(cfg)
+	registry := hsync.BuildRegistry(cfg, st)
 
 	fmt.Println("☕ Syncing sources...")
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
-	results := hsync.SyncAll(ctx, st, registry)
+	results := hsync.SyncAll(context.Background(), st, registry, opts)
 	hsync.PrintResults(results)
 
 	fmt.Printf("\nTotal items in store: %d\n", st.ItemCount())
@@ -91,50 +152,3 @@ func (r *Root) cmdSyncRemote() error {
 	fmt.Printf("Theme: %v\n", remoteCfg["theme"])
 	return nil
 }
-
-func buildRegistry(cfg *config.Config) *source.Registry {
-	registry := source.NewRegistry()
-	prof := profile.Load(cfg.GetProfileName())
-	for _, spec := range prof.Sources {
-		if spec.ConfigKey != "" {
-			if srcCfg, ok := cfg.Sources[spec.ConfigKey]; !ok || !srcCfg.Enabled {
-				continue
-			}
-		}
-
-		src := instantiateSource(spec)
-		if src == nil {
-			continue
-		}
-		registry.Register(spec.Key, src)
-	}
-	return registry
-}
-
-func instantiateSource(spec profile.SourceSpec) source.Source {
-	switch spec.Driver {
-	case "hackernews":
-		return hackernews.New()
-	case "hnsearch":
-		return hnsearch.New(spec.Name, spec.Queries, spec.Icon)
-	case "github-trending":
-		return github.New(spec.Name, spec.Topics, spec.Icon)
-	case "tldr":
-		if spec.FeedURL == "" {
-			return nil
-		}
-		return tldr.New(spec.Name, spec.FeedURL, spec.Icon)
-	case "lobsters":
-		return lobsters.New(spec.Name, spec.Tags, spec.Icon)
-	case "reddit":
-		return reddit.New(spec.Name, spec.Subreddits, spec.Icon)
-	case "arxiv":
-		cats := spec.Categories
-		if len(cats) == 0 {
-			cats = arxiv.DefaultCategories
-		}
-		return arxiv.New(spec.Name, cats, spec.Icon)
-	default:
-		return nil
-	}
-}
@@ -1,6 +1,7 @@
 package profile
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -38,6 +39,16 @@ func Load(name string) *Profile {
 	return Default()
 }
 
+// Read returns the sources saved under name, without falling back to the
+// default profile. A profile that does not exist yet is empty.
+func Read(name string) (*Profile, error) {
+	p, err := loadProfileFile(filepath.Join(dir(), name+".yaml"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Profile{}, nil
+	}
+	return p, err
+}
+
 func loadProfileFile(path string) (*Profile, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -12,7 +12,34 @@ type SourceSpec struct {
 	Tags       []string `yaml:"tags,omitempty"`
 	Subreddits []string `yaml:"subreddits,omitempty"`
 	Categories []string `yaml:"categories,omitempty"`
+	Repos      []string `yaml:"repos,omitempty"`
 	FeedURL    string   `yaml:"feed_url,omitempty"`
+	Command    []string `yaml:"command,omitempty"`
+	PageURL    string   `yaml:"page_url,omitempty"`
+	Instance   string   `yaml:"instance,omitempty"`
+	Site       string   `yaml:"site,omitempty"`
+	Server     string   `yaml:"server,omitempty"`
+	Username   string   `yaml:"username,omitempty"`
+	Folder     string   `yaml:"folder,omitempty"`
+	Accounts   []string `yaml:"accounts,omitempty"`
+	Lists      []string `yaml:"lists,omitempty"`
+	Handles    []string `yaml:"handles,omitempty"`
+	Feeds      []string `yaml:"feeds,omitempty"`
+	Channels   []string `yaml:"channels,omitempty"`
+	Playlists  []string `yaml:"playlists,omitempty"`
+	Authors    []string `yaml:"authors,omitempty"`
+
+	// Selectors are CSS selectors for the html driver, keyed by part:
+	// item, title, link, date and summary.
+	Selectors map[string]string `yaml:"selectors,omitempty"`
+
+	// Relevance maps regular expressions to the score each match adds,
+	// for drivers that rank items by keywords (arxiv).
+	Relevance map[string]float64 `yaml:"relevance,omitempty"`
+
+	// Settings are passed to the driver's Fetch, overriding any settings
+	// from the config entry named by ConfigKey.
+	Settings map[string]any `yaml:"settings,omitempty"`
 }
 
 // Profile groups the sources that should be registered.
@@ -0,0 +1,86 @@
+package source
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jcornudella/hotbrew/pkg/profile"
+)
+
+// Field describes a profile entry field or setting a driver accepts.
+type Field struct {
+	Name     string // YAML key, e.g. "feed_url" or "max"
+	Type     string // "string", "strings", "int", "float", "bool", "duration" or "map"
+	Setting  bool   // read from the entry's settings: map rather than the entry itself
+	Required bool
+	Help     string
+}
+
+// Driver builds sources of one kind from profile entries.
+type Driver struct {
+	Name   string // matched against SourceSpec.Driver
+	Help   string
+	Fields []Field
+
+	// New builds a source from spec. It returns an error when a required
+	// field is missing or invalid.
+	New func(spec profile.SourceSpec) (Source, error)
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]Driver{}
+)
+
+// RegisterDriver makes a driver available by name. Driver packages call it
+// from init; registering the same name twice panics.
+func RegisterDriver(d Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if d.New == nil {
+		panic("source: RegisterDriver " + d.Name + " without a constructor")
+	}
+	if _, dup := drivers[d.Name]; dup {
+		panic("source: RegisterDriver called twice for " + d.Name)
+	}
+	drivers[d.Name] = d
+}
+
+// LookupDriver returns the driver registered under name.
+func LookupDriver(name string) (Driver, bool) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	d, ok := drivers[name]
+	return d, ok
+}
+
+// Drivers returns every registered driver, sorted by name.
+func Drivers() []Driver {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	out := make([]Driver, 0, len(drivers))
+	for _, d := range drivers {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// FromSpec builds the source a profile entry describes.
+func FromSpec(spec profile.SourceSpec) (Source, error) {
+	d, ok := LookupDriver(spec.Driver)
+	if !ok {
+		return nil, fmt.Errorf("unknown driver %q", spec.Driver)
+	}
+	src, err := d.New(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", spec.Driver, err)
+	}
+	return src, nil
+}
+
+// MaxField is the "max" setting most drivers accept.
+func MaxField(def int) Field {
+	return Field{Name: "max", Type: "int", Setting: true, Help: fmt.Sprintf("number of items (default %d)", def)}
+}
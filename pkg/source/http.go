@@ -0,0 +1,372 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// UserAgent identifies hotbrew to the services it fetches from.
+const UserAgent = "hotbrew/1.0 (+https://github.com/jcornudella/hotbrew)"
+
+// ErrNotModified is returned by Client.Get when the server answers a
+// conditional request with 304. Sync treats it as "no new items".
+var ErrNotModified = errors.New("not modified")
+
+// ValidatorStore persists HTTP cache validators (ETag and Last-Modified)
+// per URL so conditional requests survive across runs.
+type ValidatorStore interface {
+	HTTPValidators(url string) (etag, lastModified string, ok bool)
+	SaveHTTPValidators(url, etag, lastModified string) error
+}
+
+// TokenStore persists access tokens so drivers don't request a new one on
+// every run. Keys are chosen by the driver, e.g. "reddit:<client id>".
+type TokenStore interface {
+	AuthToken(key string) (token string, expires time.Time, ok bool)
+	SaveAuthToken(key, token string, expires time.Time) error
+}
+
+// Client is the HTTP client shared by source drivers. It sets a User-Agent
+// and timeouts, retries transient failures, and can turn GETs into
+// conditional requests. Responses are gzip-compressed on the wire: the
+// transport negotiates and decodes gzip transparently as long as callers
+// leave Accept-Encoding unset.
+type Client struct {
+	HTTP      *http.Client
+	UserAgent string
+
+	// Retries for timeouts, 5xx and 429 responses. Delays grow
+	// exponentially from BaseDelay with full jitter, capped at MaxDelay.
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+
+	mu         sync.RWMutex
+	validators ValidatorStore
+	tokenStore TokenStore
+	tokens     map[string]cachedToken
+}
+
+type cachedToken struct {
+	token   string
+	expires time.Time
+}
+
+// DefaultClient is the client used by the built-in drivers.
+var DefaultClient = NewClient()
+
+// NewClient creates a client with hotbrew's default timeout and User-Agent.
+func NewClient() *Client {
+	return &Client{
+		HTTP:       &http.Client{Timeout: 30 * time.Second},
+		UserAgent:  UserAgent,
+		MaxRetries: 2,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+	}
+}
+
+// SetValidatorStore enables conditional requests backed by vs.
+// Passing nil disables them.
+func (c *Client) SetValidatorStore(vs ValidatorStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validators = vs
+}
+
+func (c *Client) validatorStore() ValidatorStore {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.validators
+}
+
+// SetTokenStore persists tokens obtained through Token in ts.
+// Passing nil keeps them in memory only.
+func (c *Client) SetTokenStore(ts TokenStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokenStore = ts
+}
+
+// Token returns the access token cached under key, calling fetch for a new
+// one when none is cached or the cached one expires within a minute.
+func (c *Client) Token(ctx context.Context, key string, fetch func(context.Context) (string, time.Time, error)) (string, error) {
+	soon := time.Now().Add(time.Minute)
+
+	c.mu.RLock()
+	cached, ok := c.tokens[key]
+	ts := c.tokenStore
+	c.mu.RUnlock()
+	if !ok && ts != nil {
+		cached.token, cached.expires, ok = ts.AuthToken(key)
+	}
+	if ok && cached.expires.After(soon) {
+		return cached.token, nil
+	}
+
+	token, expires, err := fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	if c.tokens == nil {
+		c.tokens = map[string]cachedToken{}
+	}
+	c.tokens[key] = cachedToken{token: token, expires: expires}
+	c.mu.Unlock()
+	if ts != nil {
+		ts.SaveAuthToken(key, token, expires)
+	}
+	return token, nil
+}
+
+// InvalidateToken discards the token cached under key, for when the server
+// rejects it before it expires, so the next Token call fetches a new one.
+// The new token replaces the one in the token store.
+func (c *Client) InvalidateToken(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tokens == nil {
+		c.tokens = map[string]cachedToken{}
+	}
+	c.tokens[key] = cachedToken{}
+}
+
+// Trace collects details about the HTTP requests made during a fetch, so
+// sync can record them alongside the fetch outcome.
+type Trace struct {
+	mu         sync.Mutex
+	status     int
+	validators []validators
+	afterStore []func(context.Context) error
+}
+
+// validators are the cache validators of one response.
+type validators struct {
+	url, etag, lastModified string
+}
+
+type traceKey struct{}
+
+// WithTrace returns a context that records requests made through a Client
+// into the returned Trace.
+func WithTrace(ctx context.Context) (context.Context, *Trace) {
+	t := &Trace{}
+	return context.WithValue(ctx, traceKey{}, t), t
+}
+
+// Status returns the HTTP status of the most recent response, or 0 if no
+// response was received.
+func (t *Trace) Status() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// Commit saves the cache validators of the responses fetched under t. Sync
+// calls it only once the fetched items are stored, so a fetch that fails
+// to parse or store is repeated in full rather than answered with a 304.
+func (t *Trace) Commit(vs ValidatorStore) {
+	if vs == nil {
+		return
+	}
+	t.mu.Lock()
+	pending := t.validators
+	t.validators = nil
+	t.mu.Unlock()
+	for _, v := range pending {
+		vs.SaveHTTPValidators(v.url, v.etag, v.lastModified)
+	}
+}
+
+// AfterStore registers f to run once the items fetched under ctx are
+// stored, for side effects such as marking messages read that must not
+// happen if the items are lost. It reports false, and f never runs, when
+// the caller is not storing the fetch.
+func AfterStore(ctx context.Context, f func(context.Context) error) bool {
+	t, _ := ctx.Value(traceKey{}).(*Trace)
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.afterStore = append(t.afterStore, f)
+	return true
+}
+
+// RunAfterStore runs the functions registered with AfterStore. Sync calls
+// it, like Commit, only once the fetched items are stored.
+func (t *Trace) RunAfterStore(ctx context.Context) error {
+	t.mu.Lock()
+	pending := t.afterStore
+	t.afterStore = nil
+	t.mu.Unlock()
+	var errs []error
+	for _, f := range pending {
+		if err := f(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t *Trace) record(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	t.mu.Lock()
+	t.status = resp.StatusCode
+	t.mu.Unlock()
+}
+
+// Do sends req, filling in the User-Agent if the caller has not set one.
+// Timeouts, 5xx and 429 responses are retried with jittered exponential
+// backoff; a 429's Retry-After is honored when it fits within MaxDelay.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	trace, _ := req.Context().Value(traceKey{}).(*Trace)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.HTTP.Do(req)
+		if trace != nil {
+			trace.record(resp)
+		}
+		if attempt >= c.MaxRetries || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		delay := c.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				if after > c.MaxDelay {
+					// The server asked for more patience than we have.
+					return resp, nil
+				}
+				delay = after
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether a request outcome is worth another attempt.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff returns a random delay in [0, BaseDelay*2^attempt), capped at MaxDelay.
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.BaseDelay << attempt
+	if ceiling <= 0 || ceiling > c.MaxDelay {
+		ceiling = c.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// Get issues a GET for url with the given extra headers. If validators from
+// a previous response are known, the request is conditional and a 304 reply
+// yields ErrNotModified. Other statuses are returned for the caller to check.
+// New validators are held in the context's Trace until Trace.Commit; without
+// a Trace they are not saved.
+func (c *Client) Get(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	vs := c.validatorStore()
+	if vs != nil {
+		if etag, lastModified, ok := vs.HTTPValidators(url); ok {
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			if lastModified != "" {
+				req.Header.Set("If-Modified-Since", lastModified)
+			}
+		}
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return nil, ErrNotModified
+	}
+	if vs == nil || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	trace, _ := ctx.Value(traceKey{}).(*Trace)
+	if (etag == "" && lastModified == "") || trace == nil {
+		return resp, nil
+	}
+	trace.mu.Lock()
+	trace.validators = append(trace.validators, validators{url: url, etag: etag, lastModified: lastModified})
+	trace.mu.Unlock()
+	return resp, nil
+}
@@ -4,6 +4,8 @@ package source
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -52,6 +54,15 @@ type Item struct {
 	Metadata  map[string]any
 }
 
+// FormatDuration renders a length in seconds, such as the "duration"
+// metadata of video and podcast items, as m:ss or h:mm:ss.
+func FormatDuration(secs int) string {
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
 // Section represents a group of items from a source
 type Section struct {
 	Name     string
@@ -66,6 +77,79 @@ type Config struct {
 	Settings map[string]any
 }
 
+// Int returns an integer setting, or def if it is missing.
+// Settings decoded from JSON hold numbers as float64, so both forms are accepted.
+func (c Config) Int(key string, def int) int {
+	switch v := c.Settings[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return def
+	}
+}
+
+// String returns a string setting, or def if it is missing or empty.
+func (c Config) String(key, def string) string {
+	if v, ok := c.Settings[key].(string); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+// Float returns a numeric setting, or def if it is missing.
+func (c Config) Float(key string, def float64) float64 {
+	switch v := c.Settings[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return def
+	}
+}
+
+// Strings returns a string-list setting, or nil if it is missing.
+// Lists from YAML or JSON arrive as []any; a comma-separated string is
+// also accepted.
+func (c Config) Strings(key string) []string {
+	switch v := c.Settings[key].(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	case string:
+		var out []string
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// Bool returns a boolean setting, or def if it is missing.
+func (c Config) Bool(key string, def bool) bool {
+	if v, ok := c.Settings[key].(bool); ok {
+		return v
+	}
+	return def
+}
+
 // Source is the interface all data sources must implement
 type Source interface {
 	// Name returns the display name of the source
@@ -84,12 +168,14 @@ type Source interface {
 // Registry holds all available sources
 type Registry struct {
 	sources map[string]Source
+	configs map[string]Config
 }
 
 // NewRegistry creates a new source registry
 func NewRegistry() *Registry {
 	return &Registry{
 		sources: make(map[string]Source),
+		configs: make(map[string]Config),
 	}
 }
 
@@ -98,6 +184,21 @@ func (r *Registry) Register(name string, s Source) {
 	r.sources[name] = s
 }
 
+// RegisterWithConfig adds a source along with the config passed to its Fetch calls
+func (r *Registry) RegisterWithConfig(name string, s Source, cfg Config) {
+	r.sources[name] = s
+	r.configs[name] = cfg
+}
+
+// Config returns the fetch config for a registered source.
+// Sources registered without one get an enabled config with no settings.
+func (r *Registry) Config(name string) Config {
+	if cfg, ok := r.configs[name]; ok {
+		return cfg
+	}
+	return Config{Enabled: true}
+}
+
 // Get retrieves a source by name
 func (r *Registry) Get(name string) (Source, bool) {
 	s, ok := r.sources[name]
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 )
 
@@ -20,6 +21,29 @@ var stripParams = map[string]bool{
 	"gclid":        true,
 }
 
+// arxivPath matches arXiv abstract, PDF and HTML paths, capturing the paper
+// ID without its version: /abs/2401.01234v2, /pdf/2401.01234v1.pdf,
+// /abs/hep-th/9901001.
+var arxivPath = regexp.MustCompile(`^/(?:abs|pdf|html)/(\d{4}\.\d{4,5}|[a-z-]+(?:\.[A-Z]{2})?/\d{7})(?:v\d+)?(?:\.pdf)?/?$`)
+
+// ArxivID returns the versionless paper ID an arxiv.org URL points at.
+func ArxivID(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Hostname()) {
+	case "arxiv.org", "www.arxiv.org", "export.arxiv.org":
+	default:
+		return "", false
+	}
+	m := arxivPath.FindStringSubmatch(u.Path)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
 // CanonicalURL normalizes a URL by stripping tracking parameters,
 // trailing slashes, and lowercasing the host.
 func CanonicalURL(rawURL string) string {
@@ -32,6 +56,11 @@ func CanonicalURL(rawURL string) string {
 		return rawURL
 	}
 
+	// Every version and format of an arXiv paper is one item.
+	if id, ok := ArxivID(rawURL); ok {
+		return "https://arxiv.org/abs/" + id
+	}
+
 	// Lowercase scheme and host
 	u.Scheme = strings.ToLower(u.Scheme)
 	u.Host = strings.ToLower(u.Host)